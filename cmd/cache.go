@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/cache"
+)
+
+// CacheCommand handles `aws-ssm-connect cache <action>`
+func CacheCommand(args []string) {
+	if len(args) != 1 || args[0] != "clear" {
+		log.Fatalf("usage: aws-ssm-connect cache clear")
+	}
+	if err := cache.Clear(); err != nil {
+		log.Fatalf("clear cache failed: %v", err)
+	}
+	fmt.Println("🧹 Discovery cache cleared.")
+}
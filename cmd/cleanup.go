@@ -3,7 +3,6 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"syscall"
 
 	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
 )
@@ -11,7 +10,8 @@ import (
 func CleanupAndExit() {
 	if tunnel.CurrentPid != 0 {
 		fmt.Println("\n🔴 Closing port-forward session...")
-		_ = syscall.Kill(-tunnel.CurrentPid, syscall.SIGKILL)
+		_ = tunnel.StopSession(tunnel.CurrentPid, "interrupted")
 	}
+	stopShell("interrupted")
 	os.Exit(0)
 }
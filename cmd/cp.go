@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/audit"
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
+	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
+)
+
+// checksumTimeout bounds the remote sha256sum run through SSM
+const checksumTimeout = 5 * time.Minute
+
+// CopyFile copies a file to or from an instance over SSH-over-SSM and verifies its SHA-256:
+//
+//	aws-ssm-connect --profile dev cp ./app.conf i-0abc:/tmp/
+//	aws-ssm-connect --profile dev cp api-1:/tmp/heap.hprof .
+func CopyFile(profile string, args []string) error {
+	fs := flag.NewFlagSet("cp", flag.ExitOnError)
+	user := fs.String("user", "ec2-user", "OS user to copy as")
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		return fmt.Errorf("usage: aws-ssm-connect cp [--user ec2-user] <src> <dst>, with one side as <instance>:<path>")
+	}
+	src, dst := fs.Arg(0), fs.Arg(1)
+
+	srcHost, srcPath, srcRemote := splitRemote(src)
+	dstHost, dstPath, dstRemote := splitRemote(dst)
+	if srcRemote == dstRemote {
+		return fmt.Errorf("exactly one of source and destination must be <instance>:<path>")
+	}
+	host := srcHost
+	if dstRemote {
+		host = dstHost
+	}
+
+	instanceID, err := resolveInstanceID(profile, host)
+	if err != nil {
+		return err
+	}
+	if _, err := policy.Enforce(policy.Request{
+		Profile:    profile,
+		Instance:   host,
+		InstanceID: instanceID,
+		Target:     "cp",
+	}); err != nil {
+		return err
+	}
+	// the proxy-command scp starts checks the policy again; this confirmation covers it
+	confirmed := policy.ConfirmedEnv(instanceID)
+
+	if err := pushSSHKey(profile, instanceID, *user); err != nil {
+		fmt.Printf("⚠️ EC2 Instance Connect key push failed, trying existing keys: %v\n", err)
+	}
+
+	remote := func(p string) string { return fmt.Sprintf("%s@%s:%s", *user, instanceID, p) }
+	var localFile, remotePath, name string
+	if dstRemote {
+		info, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s is a directory; only files can be copied", src)
+		}
+		localFile, remotePath, name = src, dstPath, filepath.Base(src)
+		fmt.Printf("📦 %s → %s (%s)\n", src, dst, instanceID)
+		if err := copySCP(profile, host, instanceID, src, remote(dstPath), confirmed); err != nil {
+			return err
+		}
+	} else {
+		localFile, remotePath = dst, srcPath
+		if info, err := os.Stat(dst); err == nil && info.IsDir() {
+			localFile = filepath.Join(dst, path.Base(srcPath))
+		}
+		fmt.Printf("📦 %s (%s) → %s\n", src, instanceID, dst)
+		if err := copySCP(profile, host, instanceID, remote(srcPath), dst, confirmed); err != nil {
+			return err
+		}
+	}
+
+	localSum, err := fileSHA256(localFile)
+	if err != nil {
+		return fmt.Errorf("local checksum failed: %w", err)
+	}
+	client, err := aws.NewSSMClient(profile)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), checksumTimeout)
+	defer cancel()
+	remoteSum, err := aws.RemoteSHA256(ctx, client, instanceID, *user, remotePath, name)
+	if err != nil {
+		return fmt.Errorf("remote checksum failed: %w", err)
+	}
+	if localSum != remoteSum {
+		return fmt.Errorf("❌ checksum mismatch: local %s, remote %s", localSum, remoteSum)
+	}
+	fmt.Printf("🔐 SHA-256 verified: %s\n", localSum)
+	return nil
+}
+
+// copySCP runs scp between src and dst, recording the copy and its exit status in the audit log
+func copySCP(profile, host, instanceID, src, dst, confirmed string) error {
+	c, err := tunnel.SCPCommand(profile, src, dst, confirmed)
+	if err != nil {
+		return err
+	}
+	err = runRecorded(c, audit.Record{
+		Kind:       "cp",
+		Profile:    profile,
+		Instance:   host,
+		InstanceID: instanceID,
+		Target:     src + " → " + dst,
+	})
+	if err != nil {
+		return fmt.Errorf("scp failed: %w", err)
+	}
+	return nil
+}
+
+// splitRemote splits an <instance>:<path> argument; local paths containing a colon need a slash before it
+func splitRemote(arg string) (host, p string, remote bool) {
+	host, p, found := strings.Cut(arg, ":")
+	if !found || host == "" || strings.Contains(host, "/") {
+		return "", arg, false
+	}
+	return host, p, true
+}
+
+// fileSHA256 returns the hex SHA-256 of a local file
+func fileSHA256(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -6,29 +6,31 @@ import (
 	"strings"
 
 	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
 	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
 	"github.com/ilkerispir/aws-ssm-connect/internal/ui"
 	"github.com/manifoldco/promptui"
 )
 
-// ConnectToDBProxy establishes port-forwarding to a selected DB proxy behind an EC2 instance
-func ConnectToDBProxy(profile string, port int) error {
-	instances, err := aws.FetchInstances(profile)
+// ConnectToDBProxy establishes port-forwarding to a selected target behind an EC2 instance
+func ConnectToDBProxy(profile string, port int, refresh bool) error {
+	instances, hostWarnings, err := aws.LoadJumpHosts(profile, refresh)
 	if err != nil {
 		return fmt.Errorf("failed to fetch instances: %w", err)
 	}
+	ui.PrintWarnings(hostWarnings)
 	if len(instances) == 0 {
-		return fmt.Errorf("no SSM-managed EC2 instances found")
+		return fmt.Errorf("no SSM-managed EC2 instances or ECS tasks found")
 	}
 
 	// Prompt EC2 instance selection
 	var instOptions []string
 	for _, inst := range instances {
-		instOptions = append(instOptions, fmt.Sprintf("🖥️  %s (%s)", inst.Name, inst.ID))
+		instOptions = append(instOptions, ui.FormatInstanceLabel(inst))
 	}
 
 	instPrompt := promptui.Select{
-		Label: "Select EC2 Instance",
+		Label: "Select EC2 Instance or ECS Task",
 		Items: instOptions,
 		Searcher: func(input string, index int) bool {
 			return strings.Contains(strings.ToLower(instOptions[index]), strings.ToLower(input))
@@ -40,35 +42,45 @@ func ConnectToDBProxy(profile string, port int) error {
 	}
 
 	selectedInstance := instances[idx]
-	dbs, err := aws.FetchDBs(profile)
+	targets, warnings, err := aws.LoadTargets(profile, refresh)
 	if err != nil {
-		return fmt.Errorf("failed to fetch databases: %w", err)
+		return fmt.Errorf("failed to fetch targets: %w", err)
 	}
+	ui.PrintWarnings(warnings)
 
-	// Filter DBs in same VPC
-	var candidates []aws.DB
+	// Filter targets in same VPC
+	var candidates []aws.Target
 	var labels []string
-	for _, db := range dbs {
-		if db.VpcID == selectedInstance.VpcID {
-			candidates = append(candidates, db)
-			labels = append(labels, ui.FormatDBLabel(db))
+	for _, t := range targets {
+		if t.VpcID == selectedInstance.VpcID {
+			candidates = append(candidates, t)
+			labels = append(labels, ui.FormatTargetLabel(t))
 		}
 	}
 
 	if len(candidates) == 0 {
-		return fmt.Errorf("no databases found in same VPC as EC2 instance")
+		return fmt.Errorf("no targets found in same VPC as EC2 instance")
 	}
 
 	dbPrompt := promptui.Select{
-		Label: "Select DB Proxy to forward",
+		Label: "Select target to forward",
 		Items: labels,
 		Searcher: func(input string, index int) bool {
 			return strings.Contains(strings.ToLower(labels[index]), strings.ToLower(input))
 		},
 	}
-	dbIdx, _, err := dbPrompt.Run()
+	// protected sessions start on a reader endpoint
+	protected, err := policy.Protected(profile, selectedInstance)
 	if err != nil {
-		return fmt.Errorf("db selection prompt failed: %w", err)
+		return err
+	}
+	cursor := 0
+	if protected {
+		cursor = ui.ReaderFirst(candidates)
+	}
+	dbIdx, _, err := ui.RunSelectAt(&dbPrompt, cursor)
+	if err != nil {
+		return fmt.Errorf("target selection prompt failed: %w", err)
 	}
 
 	selectedDB := candidates[dbIdx]
@@ -78,5 +90,11 @@ func ConnectToDBProxy(profile string, port int) error {
 	}
 
 	log.Printf("🔗 Connecting to %s via %s (%s)...", selectedDB.Endpoint, selectedInstance.Name, selectedInstance.ID)
-	return tunnel.StartPortForward(profile, selectedInstance.Name, selectedInstance.ID, selectedDB.Endpoint, selectedDB.Port, localPort)
+	if err := tunnel.StartPortForward(profile, selectedInstance.Name, selectedInstance.ID, selectedDB.Endpoint, selectedDB.Port, localPort); err != nil {
+		return err
+	}
+	if hint := ui.ConnectionHint(selectedDB, localPort); hint != "" {
+		fmt.Println(hint)
+	}
+	return nil
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
+	"github.com/manifoldco/promptui"
+)
+
+// EKSForward tunnels a local port to a Service ClusterIP or pod IP through a node of the EKS cluster:
+//
+//	aws-ssm-connect --profile dev eks-forward --cluster prod 172.20.14.3:80
+func EKSForward(profile string, args []string, port int, refresh bool) error {
+	fs := flag.NewFlagSet("eks-forward", flag.ExitOnError)
+	cluster := fs.String("cluster", "", "EKS cluster whose nodes to tunnel through (prompted if omitted)")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: aws-ssm-connect eks-forward [--cluster <name>] <ip>:<port>")
+	}
+
+	host, remotePort, err := net.SplitHostPort(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("target must be <ip>:<port>: %w", err)
+	}
+	// nodes resolve names through VPC DNS, not the cluster's CoreDNS
+	if net.ParseIP(host) == nil {
+		return fmt.Errorf("%s is not an IP; use the Service ClusterIP or pod IP (kubectl get svc,pods -o wide)", host)
+	}
+	if p, err := strconv.Atoi(remotePort); err != nil || p <= 0 || p > 65535 {
+		return fmt.Errorf("invalid remote port %q", remotePort)
+	}
+
+	instances, err := aws.LoadInstances(profile, refresh)
+	if err != nil {
+		return fmt.Errorf("failed to fetch instances: %w", err)
+	}
+	clusters := aws.EKSClusters(instances)
+	if len(clusters) == 0 {
+		return fmt.Errorf("no SSM-managed EKS nodes found for profile %s", profile)
+	}
+	if *cluster == "" {
+		prompt := promptui.Select{Label: "Select EKS cluster", Items: clusters}
+		idx, _, err := prompt.Run()
+		if err != nil {
+			return fmt.Errorf("prompt failed: %w", err)
+		}
+		*cluster = clusters[idx]
+	}
+
+	nodes := aws.RankNodes(instances, *cluster)
+	if len(nodes) == 0 {
+		return fmt.Errorf("no SSM-managed nodes found in EKS cluster %s", *cluster)
+	}
+	node := nodes[0]
+	fmt.Printf("☸️ Using node %s (%s) of %s/%s\n", node.Name, node.ID, node.EKSCluster, node.NodeGroup)
+
+	localPort := remotePort
+	if port != 0 {
+		localPort = strconv.Itoa(port)
+	}
+	return tunnel.StartPortForward(profile, node.Name, node.ID, host, remotePort, localPort)
+}
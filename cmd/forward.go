@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
+	"github.com/ilkerispir/aws-ssm-connect/internal/ui"
+)
+
+// ForwardInstancePort forwards a local port to a port on the EC2 instance itself,
+// picking the first instance matching filter or prompting when filter is empty
+func ForwardInstancePort(profile, filter string, remotePort, port int, refresh bool) error {
+	if remotePort <= 0 || remotePort > 65535 {
+		return fmt.Errorf("--remote-port must be between 1 and 65535")
+	}
+
+	instances, warnings, err := aws.LoadJumpHosts(profile, refresh)
+	if err != nil {
+		return fmt.Errorf("failed to fetch instances: %w", err)
+	}
+	ui.PrintWarnings(warnings)
+	if len(instances) == 0 {
+		return fmt.Errorf("no SSM-managed EC2 instances or ECS tasks found")
+	}
+
+	selected, err := selectInstance(instances, filter)
+	if err != nil {
+		return err
+	}
+
+	localPort := strconv.Itoa(remotePort)
+	if port != 0 {
+		localPort = strconv.Itoa(port)
+	}
+
+	return tunnel.StartInstancePortForward(profile, selected.Name, selected.ID, strconv.Itoa(remotePort), localPort)
+}
+
+// selectInstance picks the first instance whose name matches filter, or prompts when filter is empty
+func selectInstance(instances []aws.Instance, filter string) (aws.Instance, error) {
+	if filter == "" {
+		selected, err := ui.PromptInstance(instances)
+		if err != nil {
+			return aws.Instance{}, fmt.Errorf("prompt failed: %w", err)
+		}
+		return selected, nil
+	}
+	for _, inst := range instances {
+		if strings.Contains(strings.ToLower(inst.Name), strings.ToLower(filter)) {
+			return inst, nil
+		}
+	}
+	return aws.Instance{}, fmt.Errorf("no instance matching filter '%s' found", filter)
+}
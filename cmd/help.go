@@ -3,17 +3,26 @@ package cmd
 import "fmt"
 
 func ShowHelper() {
-	fmt.Println(`
+	fmt.Print(`
 AWS SSM Tunnel CLI
 
 Usage:
   aws-ssm-connect                                          # Interactive mode (prompts)
   aws-ssm-connect --profile <profile> --filter <keyword>   # Quick connect to database
   aws-ssm-connect --ssm --profile <profile>                # Start SSM shell session to EC2
-  aws-ssm-connect --db-port-forward --profile <profile>    # Port-forward to a selected DB proxy via EC2
+  aws-ssm-connect --db-port-forward --profile <profile>    # Port-forward to a selected target via EC2
+  aws-ssm-connect --forward --remote-port <port>           # Port-forward to a port on the EC2 instance itself
+  aws-ssm-connect --remote-host <host:port>                # Tunnel to any host reachable from the EC2 instance
   aws-ssm-connect --list                                   # List active port-forward sessions
   aws-ssm-connect --kill <pid>                             # Kill a specific port-forward session by PID
   aws-ssm-connect --kill-all                               # Kill all active port-forward sessions
+  aws-ssm-connect eks-forward [--cluster <name>] <ip:port> # Tunnel to a Service ClusterIP or pod IP through an EKS node
+  aws-ssm-connect shell [--filter <name>]                  # Open an ECS Exec shell in a running container
+  aws-ssm-connect run --instance-tag <Key=Value> -- <cmd>  # Run a shell command on every matching instance
+  aws-ssm-connect cp <src> <instance>:<path>               # Copy a file to or from an instance over SSM (checksum verified)
+  aws-ssm-connect ssh-config [-]                           # Write SSH Host entries for SSM-managed instances ("-" prints them)
+  aws-ssm-connect proxy-command <host> <port> [user]       # SSH ProxyCommand over SSM (with --profile); pushes a key for user via EC2 Instance Connect
+  aws-ssm-connect cache clear                              # Remove cached instance and database lists
   aws-ssm-connect --help                                   # Show this helper message
   aws-ssm-connect --version                                # Show version
 
@@ -21,19 +30,39 @@ Flags:
 --profile            AWS profile to use (e.g., dev, prod)
 --filter             Filter for EC2 instance name (for DB tunneling)
 --port               Local port override (optional)
---ssm                Start standard SSM shell session to EC2 instance
---db-port-forward    Port-forward to a selected RDS proxy via EC2
+--ssm                Start SSM shell session to EC2 instance
+--document           Session document for --ssm (default SSM-SessionManagerRunShell)
+--pick-document      Choose the --ssm session document from the account's Session documents
+--run-as             OS user to run the --ssm shell as
+--command            Shell or initial command for the --ssm session (e.g. "sh", "htop")
+--db-port-forward    Port-forward to a selected database, cache, Redshift, OpenSearch or MSK target via EC2 or an ECS task
+--forward            Port-forward to a port on the EC2 instance itself (admin UIs, local databases, RDP)
+--remote-port        Port on the instance to forward to (with --forward; local port defaults to the same)
+--remote-host        Tunnel to any host:port through the instance; private DNS names are resolved on the instance
+--ttl                Close port-forwards after this long (e.g. 2h); --list shows the time left
+--idle-timeout       Close port-forwards after this long without traffic (e.g. 30m)
 --list               Show active port-forward sessions
 --kill               Kill a session by PID
 --kill-all           Kill all active sessions
+--refresh            Bypass the discovery cache and fetch fresh results
 --version            Show version info
 --help               Show this help message
 
 Examples:
 aws-ssm-connect --profile dev --filter prod-db
 aws-ssm-connect --ssm --profile dev
+aws-ssm-connect --ssm --profile prod --document Org-ShellSession --run-as ops
 aws-ssm-connect --db-port-forward --profile dev
+aws-ssm-connect --forward --remote-port 3389 --port 13389 --profile dev --filter windows
+aws-ssm-connect --remote-host internal-api.corp.local:443 --port 8443 --profile dev
+aws-ssm-connect --profile dev ssh-config && ssh ec2-user@dev.web-1
+aws-ssm-connect --profile dev run --instance-tag Role=api --max-concurrency 5 --max-errors 1 -- 'df -h'
+aws-ssm-connect --profile dev cp --user ec2-user api-1:/tmp/heap.hprof .
+aws-ssm-connect --profile dev shell --filter api --command /bin/bash
+aws-ssm-connect --profile dev --port 8080 eks-forward --cluster prod 172.20.14.3:80
+aws-ssm-connect --profile prod --filter bastion --ttl 2h --idle-timeout 30m
 aws-ssm-connect --kill 12345
 aws-ssm-connect --list
+aws-ssm-connect --refresh --profile dev --filter prod-db
 `)
 }
@@ -5,12 +5,13 @@ import (
 	"log"
 
 	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
 	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
 	"github.com/ilkerispir/aws-ssm-connect/internal/ui"
 )
 
-// Interactive mode with profile, instance and DB prompts
-func Interactive() error {
+// Interactive mode with profile, instance and target prompts
+func Interactive(refresh bool) error {
 	profiles, err := aws.FetchProfiles()
 	if err != nil {
 		return fmt.Errorf("load profiles failed: %w", err)
@@ -25,30 +26,36 @@ func Interactive() error {
 		return fmt.Errorf("SSO login failed: %w", err)
 	}
 
-	instances, err := aws.FetchInstances(profile)
+	instances, hostWarnings, err := aws.LoadJumpHosts(profile, refresh)
 	if err != nil {
 		return fmt.Errorf("fetch instances failed: %w", err)
 	}
+	ui.PrintWarnings(hostWarnings)
 
 	instance, err := ui.PromptInstance(instances)
 	if err != nil {
 		return fmt.Errorf("instance prompt failed: %w", err)
 	}
 
-	dbs, err := aws.FetchDBs(profile)
+	targets, warnings, err := aws.LoadTargets(profile, refresh)
 	if err != nil {
-		return fmt.Errorf("fetch dbs failed: %w", err)
+		return fmt.Errorf("fetch targets failed: %w", err)
 	}
+	ui.PrintWarnings(warnings)
 
-	filtered := ui.FilterDBsByVPC(dbs, instance.VpcID)
+	filtered := ui.FilterTargetsByVPC(targets, instance.VpcID)
 	if len(filtered) == 0 {
-		fmt.Println("No databases found in the same VPC.")
+		fmt.Println("No targets found in the same VPC.")
 		return nil
 	}
 
-	db, err := ui.PromptDatabase(filtered)
+	protected, err := policy.Protected(profile, instance)
 	if err != nil {
-		return fmt.Errorf("database prompt failed: %w", err)
+		return err
+	}
+	db, err := ui.PromptTarget(filtered, protected)
+	if err != nil {
+		return fmt.Errorf("target prompt failed: %w", err)
 	}
 
 	err = tunnel.WriteLastSelection(&tunnel.LastSelection{
@@ -68,6 +75,9 @@ func Interactive() error {
 	if err != nil {
 		return fmt.Errorf("port forwarding failed: %w", err)
 	}
+	if hint := ui.ConnectionHint(db, localPort); hint != "" {
+		fmt.Println(hint)
+	}
 
 	return nil
 }
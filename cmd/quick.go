@@ -7,12 +7,14 @@ import (
 	"strings"
 
 	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
 	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
+	"github.com/ilkerispir/aws-ssm-connect/internal/ui"
 )
 
 // QuickConnect establishes a port-forward by filtering instance + selecting DB in same VPC
-func QuickConnect(profile, filter string, overridePort int) {
-	instances, err := aws.FetchInstances(profile)
+func QuickConnect(profile, filter string, overridePort int, refresh bool) {
+	instances, err := aws.LoadInstances(profile, refresh)
 	if err != nil {
 		log.Fatalf("fetch instances failed: %v", err)
 	}
@@ -28,18 +30,36 @@ func QuickConnect(profile, filter string, overridePort int) {
 		log.Fatalf("no instance matching filter '%s' found", filter)
 	}
 
-	dbs, err := aws.FetchDBs(profile)
+	targets, warnings, err := aws.LoadTargets(profile, refresh)
 	if err != nil {
-		log.Fatalf("fetch dbs failed: %v", err)
+		log.Fatalf("fetch targets failed: %v", err)
 	}
+	ui.PrintWarnings(warnings)
 
-	var selectedDB *aws.DB
-	for _, db := range dbs {
-		if db.VpcID == selectedInstance.VpcID && db.Role == "writer" {
-			selectedDB = &db
+	// protected profiles get the reader endpoint of the cluster when it has one
+	role := "writer"
+	protected, err := policy.Protected(profile, *selectedInstance)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	if protected {
+		role = "reader"
+	}
+	var selectedDB *aws.Target
+	for _, t := range targets {
+		if t.VpcID == selectedInstance.VpcID && t.Role == role {
+			selectedDB = &t
 			break
 		}
 	}
+	if selectedDB == nil && role == "reader" {
+		for _, t := range targets {
+			if t.VpcID == selectedInstance.VpcID && t.Role == "writer" {
+				selectedDB = &t
+				break
+			}
+		}
+	}
 	if selectedDB == nil {
 		log.Fatalf("no writer database found for selected instance")
 	}
@@ -78,4 +98,7 @@ func QuickConnect(profile, filter string, overridePort int) {
 	if err != nil {
 		log.Fatalf("port forwarding failed: %v", err)
 	}
+	if hint := ui.ConnectionHint(*selectedDB, localPort); hint != "" {
+		fmt.Println(hint)
+	}
 }
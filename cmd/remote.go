@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
+	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
+	"github.com/ilkerispir/aws-ssm-connect/internal/ui"
+)
+
+// resolveTimeout bounds the DNS lookup run on the instance
+const resolveTimeout = 30 * time.Second
+
+// ForwardRemoteHost tunnels a local port to any host:port reachable from the selected instance.
+// Names that do not resolve locally, such as private hosted zone records, are checked from the instance.
+func ForwardRemoteHost(profile, filter, remote string, port int, refresh bool) error {
+	host, remotePort, err := net.SplitHostPort(remote)
+	if err != nil {
+		return fmt.Errorf("--remote-host must be host:port: %w", err)
+	}
+	if p, err := strconv.Atoi(remotePort); err != nil || p <= 0 || p > 65535 {
+		return fmt.Errorf("invalid remote port %q", remotePort)
+	}
+
+	instances, warnings, err := aws.LoadJumpHosts(profile, refresh)
+	if err != nil {
+		return fmt.Errorf("failed to fetch instances: %w", err)
+	}
+	ui.PrintWarnings(warnings)
+	if len(instances) == 0 {
+		return fmt.Errorf("no SSM-managed EC2 instances or ECS tasks found")
+	}
+	selected, err := selectInstance(instances, filter)
+	if err != nil {
+		return err
+	}
+
+	if _, err := net.LookupHost(host); err != nil {
+		// Run Command only reaches EC2 instances, not ECS managed agents
+		if aws.IsTaskTarget(selected.ID) {
+			return fmt.Errorf("%s does not resolve locally and cannot be checked from an ECS task: %w", host, err)
+		}
+		// the lookup runs on the instance, so the policy applies before it as it does to the tunnel
+		if _, err := policy.Enforce(policy.Request{
+			Profile:    profile,
+			Instance:   selected.Name,
+			InstanceID: selected.ID,
+			Target:     net.JoinHostPort(host, remotePort),
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("🔎 %s does not resolve locally, checking from %s (%s)...\n", host, selected.Name, selected.ID)
+		client, err := aws.NewSSMClient(profile)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+		defer cancel()
+		addrs, err := aws.ResolveOnInstance(ctx, client, selected.ID, host)
+		if err != nil {
+			return fmt.Errorf("resolve on instance failed: %w", err)
+		}
+		if len(addrs) == 0 {
+			return fmt.Errorf("%s does not resolve from %s either", host, selected.ID)
+		}
+		fmt.Printf("✔ %s → %s\n", host, strings.Join(addrs, ", "))
+	}
+
+	localPort := remotePort
+	if port != 0 {
+		localPort = strconv.Itoa(port)
+	}
+	return tunnel.StartPortForward(profile, selected.Name, selected.ID, host, remotePort, localPort)
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
+)
+
+// statusIcons decorates command statuses in run output
+var statusIcons = map[string]string{
+	"Success":   "✅",
+	"Failed":    "❌",
+	"TimedOut":  "⏱️",
+	"Cancelled": "⏭️",
+}
+
+// RunCommand runs a shell command on every instance matched by --instance-tag:
+//
+//	aws-ssm-connect --profile dev run --instance-tag Role=api -- 'df -h'
+func RunCommand(profile string, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	selector := fs.String("instance-tag", "", "Key=Value tag, Name tag or instance ID selecting the instances")
+	maxConcurrency := fs.String("max-concurrency", "50", "Instances running the command at once (number or percentage)")
+	maxErrors := fs.String("max-errors", "0", "Failures allowed across all instances before the command is cancelled (number or percentage)")
+	timeout := fs.Duration("timeout", 30*time.Minute, "Give up waiting and cancel the command after this long")
+	_ = fs.Parse(args)
+
+	command := strings.Join(fs.Args(), " ")
+	if *selector == "" || command == "" {
+		return fmt.Errorf("usage: aws-ssm-connect run --instance-tag Key=Value [--max-concurrency N] [--max-errors N] -- <command>")
+	}
+
+	instances, err := aws.FetchInstances(profile)
+	if err != nil {
+		return fmt.Errorf("fetch instances failed: %w", err)
+	}
+	matched := aws.MatchInstances(instances, *selector)
+	if len(matched) == 0 {
+		return fmt.Errorf("no SSM-managed instance matches %q", *selector)
+	}
+
+	client, err := aws.NewSSMClient(profile)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(matched))
+	reqs := make([]policy.Request, len(matched))
+	for i, inst := range matched {
+		ids[i] = inst.ID
+		reqs[i] = policy.Request{Profile: profile, Instance: inst.Name, InstanceID: inst.ID, Tags: inst.Tags, Target: "run"}
+	}
+	maxDuration, err := policy.EnforceAll(reqs)
+	if err != nil {
+		return err
+	}
+	if maxDuration > 0 && maxDuration < *timeout {
+		*timeout = maxDuration
+	}
+	fmt.Printf("🚀 Running %q on %d instance(s)...\n", command, len(ids))
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	results, err := aws.RunFleetCommand(ctx, client, ids, []string{command}, aws.FleetOptions{
+		MaxConcurrency: *maxConcurrency,
+		MaxErrors:      *maxErrors,
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(matched, func(i, j int) bool { return matched[i].Name < matched[j].Name })
+	counts := map[string]int{}
+	for _, inst := range matched {
+		result, ok := results[inst.ID]
+		if !ok {
+			result = aws.CommandResult{Status: "NotSent"}
+		}
+		counts[result.Status]++
+
+		icon, ok := statusIcons[result.Status]
+		if !ok {
+			icon = "❔"
+		}
+		// only commands that ran have an exit code
+		if result.Status == "Success" || result.Status == "Failed" {
+			fmt.Printf("\n%s 🖥️ %s (%s) %s (exit %d)\n", icon, inst.Name, inst.ID, result.Status, result.ExitCode)
+		} else {
+			fmt.Printf("\n%s 🖥️ %s (%s) %s\n", icon, inst.Name, inst.ID, result.Status)
+		}
+		if out := strings.TrimRight(result.Stdout, "\n"); out != "" {
+			fmt.Println(out)
+		}
+		if errOut := strings.TrimRight(result.Stderr, "\n"); errOut != "" {
+			fmt.Printf("⚠️ stderr:\n%s\n", errOut)
+		}
+	}
+
+	var statuses []string
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	var summary []string
+	for _, status := range statuses {
+		summary = append(summary, fmt.Sprintf("%d %s", counts[status], status))
+	}
+	fmt.Printf("\n📊 %d instance(s): %s\n", len(matched), strings.Join(summary, ", "))
+
+	if failed := len(matched) - counts["Success"]; failed > 0 {
+		return fmt.Errorf("%d of %d instances did not succeed", failed, len(matched))
+	}
+	return nil
+}
@@ -1,11 +1,26 @@
 package cmd
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/ilkerispir/aws-ssm-connect/internal/audit"
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
 	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
 )
 
+var (
+	shellMu sync.Mutex
+	// activeShell is the foreground session still open, recorded as interrupted on Ctrl+C
+	activeShell *audit.Record
+)
+
 func ListSessions() {
 	if err := tunnel.ListPIDs(); err != nil {
 		log.Fatalf("list sessions failed: %v", err)
@@ -18,8 +33,89 @@ func KillSession(pid int) {
 	}
 }
 
+// SetTunnelLimits applies --ttl and --idle-timeout to the port-forwards started by this run
+func SetTunnelLimits(ttl, idleTimeout time.Duration) {
+	tunnel.SetLimits(tunnel.Limits{TTL: ttl, IdleTimeout: idleTimeout})
+}
+
+// Supervise runs the detached process that enforces a tunnel's TTL and idle timeout
+func Supervise(args []string) {
+	if err := tunnel.Supervise(args); err != nil {
+		log.Fatalf("supervise failed: %v", err)
+	}
+}
+
 func KillAllSessions() {
 	if err := tunnel.KillAllPIDs(); err != nil {
 		log.Fatalf("kill all sessions failed: %v", err)
 	}
 }
+
+// runRecorded runs an interactive session in the foreground once the policy file allows it,
+// writing its start and end to the audit log. rec.Kind defaults to "shell".
+func runRecorded(c *exec.Cmd, rec audit.Record) error {
+	rec.Caller = audit.Caller(rec.Profile)
+	maxDuration, err := policy.Enforce(policy.Request{
+		Profile:    rec.Profile,
+		Account:    aws.AccountFromARN(rec.Caller),
+		Instance:   rec.Instance,
+		InstanceID: rec.InstanceID,
+		Target:     rec.Target,
+	})
+	if err != nil {
+		return err
+	}
+
+	rec.Event = audit.EventStart
+	if rec.Kind == "" {
+		rec.Kind = "shell"
+	}
+	rec.Started = time.Now().UTC()
+	if err := c.Start(); err != nil {
+		return err
+	}
+	audit.Log(rec)
+
+	shellMu.Lock()
+	activeShell = &rec
+	shellMu.Unlock()
+
+	var expired atomic.Bool
+	if maxDuration > 0 {
+		timer := time.AfterFunc(maxDuration, func() {
+			expired.Store(true)
+			fmt.Fprintf(os.Stderr, "\n⏱️ Maximum session duration of %s reached, closing\n", maxDuration)
+			_ = c.Process.Kill()
+		})
+		defer timer.Stop()
+	}
+
+	err = c.Wait()
+
+	reason := "exited"
+	switch {
+	case expired.Load():
+		reason = "max duration reached"
+	case err != nil:
+		reason = err.Error()
+	}
+	stopShell(reason)
+	return err
+}
+
+// stopShell records the end of the active foreground session, if any
+func stopShell(reason string) {
+	shellMu.Lock()
+	defer shellMu.Unlock()
+	if activeShell == nil {
+		return
+	}
+	rec := *activeShell
+	activeShell = nil
+
+	rec.Time = time.Time{}
+	rec.Event = audit.EventStop
+	rec.Stopped = time.Now().UTC()
+	rec.Reason = reason
+	audit.Log(rec)
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/audit"
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/manifoldco/promptui"
+)
+
+// Shell opens an interactive ECS Exec session into a selected container:
+//
+//	aws-ssm-connect --profile dev shell [--filter api] [--command /bin/bash]
+func Shell(profile string, args []string) error {
+	fs := flag.NewFlagSet("shell", flag.ExitOnError)
+	filter := fs.String("filter", "", "Only list containers whose cluster/service/container name contains this")
+	command := fs.String("command", "/bin/sh", "Command to run in the container")
+	_ = fs.Parse(args)
+
+	tasks, err := aws.FetchTasks(profile)
+	if err != nil {
+		if len(tasks) == 0 {
+			return fmt.Errorf("fetch ECS tasks failed: %w", err)
+		}
+		fmt.Printf("⚠️ Some ECS tasks could not be listed: %v\n", err)
+	}
+
+	var candidates []aws.Task
+	var labels []string
+	for _, t := range tasks {
+		if strings.Contains(strings.ToLower(t.Label()), strings.ToLower(*filter)) {
+			candidates = append(candidates, t)
+			labels = append(labels, fmt.Sprintf("🐳 %s (%s)", t.Label(), t.TaskID))
+		}
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("no running ECS containers with ECS Exec enabled found for profile %s", profile)
+	}
+
+	prompt := promptui.Select{
+		Label: fmt.Sprintf("Select container (profile: %s)", profile),
+		Items: labels,
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(strings.ToLower(labels[index]), strings.ToLower(input))
+		},
+	}
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return fmt.Errorf("prompt failed: %w", err)
+	}
+	task := candidates[idx]
+
+	fmt.Printf("\n✅ Starting ECS Exec session to: %s\n\n", task.Label())
+
+	cmd := exec.Command(
+		"aws", "ecs", "execute-command",
+		"--profile", profile,
+		"--cluster", task.Cluster,
+		"--task", task.TaskID,
+		"--container", task.Container,
+		"--interactive",
+		"--command", *command,
+	)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return runRecorded(cmd, audit.Record{
+		Profile:    profile,
+		Instance:   task.Label(),
+		InstanceID: task.Target(),
+		Target:     *command,
+	})
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/audit"
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
+	"github.com/ilkerispir/aws-ssm-connect/internal/tunnel"
+)
+
+// instanceIDPattern matches EC2 instance IDs, which need no lookup
+var instanceIDPattern = regexp.MustCompile(`^i-[0-9a-f]{8,17}$`)
+
+// ProxyCommand is run by ssh as `ProxyCommand aws-ssm-connect --profile <p> proxy-command %h %p [%r]`.
+// The host may be an instance ID, a Name tag or a Key=Value tag selector. When the remote user
+// is given, a throwaway public key is pushed for it with EC2 Instance Connect first.
+func ProxyCommand(profile string, args []string) {
+	// ssh owns stdout; anything else printed (e.g. SSO login prompts) goes to stderr
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+
+	if len(args) != 2 && len(args) != 3 {
+		log.Fatalf("usage: aws-ssm-connect --profile <profile> proxy-command <host> <port> [user]")
+	}
+	if profile == "" {
+		log.Fatalf("proxy-command requires --profile")
+	}
+	host, port := args[0], args[1]
+
+	instanceID, err := resolveInstanceID(profile, host)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	// checked before the key push too; runRecorded applies the confirmation given here
+	if _, err := policy.Enforce(policy.Request{
+		Profile:    profile,
+		Instance:   host,
+		InstanceID: instanceID,
+		Target:     "ssh:" + port,
+	}); err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	if len(args) == 3 && args[2] != "" {
+		// AMIs without EC2 Instance Connect may still accept keys from authorized_keys
+		if err := pushSSHKey(profile, instanceID, args[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ EC2 Instance Connect key push failed, trying existing keys: %v\n", err)
+		}
+	}
+
+	err = runRecorded(tunnel.SSHSessionCommand(profile, instanceID, port, stdout), audit.Record{
+		Kind:       "ssh",
+		Profile:    profile,
+		Instance:   host,
+		InstanceID: instanceID,
+		Target:     "ssh:" + port,
+	})
+	if err != nil {
+		log.Fatalf("SSH session failed: %v", err)
+	}
+}
+
+// resolveInstanceID maps an instance ID, Name tag or Key=Value tag to exactly one instance ID
+func resolveInstanceID(profile, host string) (string, error) {
+	if instanceIDPattern.MatchString(host) {
+		return host, nil
+	}
+	instances, err := aws.FetchInstances(profile)
+	if err != nil {
+		return "", fmt.Errorf("fetch instances failed: %w", err)
+	}
+	matches := aws.MatchInstances(instances, host)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no SSM-managed instance matches %q", host)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		var ids []string
+		for _, m := range matches {
+			ids = append(ids, m.ID)
+		}
+		return "", fmt.Errorf("%q matches several instances (%s); use an instance ID", host, strings.Join(ids, ", "))
+	}
+}
+
+// pushSSHKey authorizes the throwaway key for osUser on the instance for the next 60 seconds
+func pushSSHKey(profile, instanceID, osUser string) error {
+	publicKey, err := tunnel.EnsureSSHKey()
+	if err != nil {
+		return err
+	}
+	return aws.PushSSHPublicKey(profile, instanceID, osUser, publicKey)
+}
+
+var (
+	sshConfigPath = filepath.Join(os.Getenv("HOME"), ".ssh", "config")
+	// sshIncludePath is the generated file; ~/.ssh/config includes it
+	sshIncludePath = filepath.Join(os.Getenv("HOME"), ".ssh", "aws-ssm-connect.conf")
+)
+
+// SSHConfig writes Host entries for every SSM-managed instance of the profile, or of every
+// profile when none is given, and makes sure ~/.ssh/config includes them.
+// With "-" as argument the entries are printed instead.
+func SSHConfig(profile string, args []string, refresh bool) {
+	profiles := []string{profile}
+	if profile == "" {
+		all, err := aws.FetchProfiles()
+		if err != nil {
+			log.Fatalf("failed to load AWS profiles: %v", err)
+		}
+		profiles = nil
+		for _, p := range all {
+			profiles = append(profiles, p.Name)
+		}
+	}
+
+	// ssh loads identities before the ProxyCommand can create the key, so create it now
+	if _, err := tunnel.EnsureSSHKey(); err != nil {
+		log.Fatalf("SSH key setup failed: %v", err)
+	}
+
+	var b strings.Builder
+	b.WriteString("# Generated by aws-ssm-connect ssh-config; changes will be overwritten\n")
+	for _, p := range profiles {
+		instances, err := aws.LoadInstances(p, refresh)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️ skipping profile %s: %v\n", p, err)
+			continue
+		}
+		if len(instances) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n# profile %s\n", p)
+		b.WriteString(sshHostEntries(p, instances))
+	}
+
+	if len(args) > 0 && args[0] == "-" {
+		fmt.Print(b.String())
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sshIncludePath), 0700); err != nil {
+		log.Fatalf("create ~/.ssh failed: %v", err)
+	}
+	if err := os.WriteFile(sshIncludePath, []byte(b.String()), 0600); err != nil {
+		log.Fatalf("write %s failed: %v", sshIncludePath, err)
+	}
+	if err := ensureSSHInclude(); err != nil {
+		log.Fatalf("update %s failed: %v", sshConfigPath, err)
+	}
+	fmt.Printf("✅ Wrote SSH config to %s\n", sshIncludePath)
+}
+
+// sshHostEntries renders the instances of a profile. Instances sharing a Name tag, such as
+// the members of an Auto Scaling group, get the end of their ID appended so every alias is unique.
+func sshHostEntries(profile string, instances []aws.Instance) string {
+	names := map[string]int{}
+	for _, inst := range instances {
+		names[sshHostName(inst)]++
+	}
+	var b strings.Builder
+	for _, inst := range instances {
+		name := sshHostName(inst)
+		if name != "" && names[name] > 1 {
+			id := strings.TrimPrefix(inst.ID, "i-")
+			name += "-" + id[max(0, len(id)-8):]
+		}
+		b.WriteString(sshHostEntry(profile, name, inst))
+	}
+	return b.String()
+}
+
+// sshHostName turns an instance's Name tag into an alias part, or "" when it has none
+func sshHostName(inst aws.Instance) string {
+	return strings.ToLower(strings.Join(strings.Fields(inst.Name), "-"))
+}
+
+// sshHostEntry renders one instance as <profile>.<name> with its ID as a second alias.
+// Passing %r lets proxy-command push the throwaway key for the user being logged in as.
+func sshHostEntry(profile, name string, inst aws.Instance) string {
+	aliases := inst.ID
+	if name != "" {
+		aliases = fmt.Sprintf("%s.%s %s", profile, name, inst.ID)
+	}
+	return fmt.Sprintf("Host %s\n  HostName %s\n  IdentityFile %s\n  ProxyCommand aws-ssm-connect --profile %s proxy-command %%h %%p %%r\n",
+		aliases, inst.ID, tunnel.SSHKeyPath, profile)
+}
+
+// ensureSSHInclude adds an Include for the generated file at the top of ~/.ssh/config.
+// Include must come before any Host block to apply to every host.
+func ensureSSHInclude() error {
+	include := "Include " + sshIncludePath
+
+	existing, err := os.ReadFile(sshConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(existing)))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == include {
+			return nil
+		}
+	}
+
+	return os.WriteFile(sshConfigPath, append([]byte(include+"\n\n"), existing...), 0600)
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+)
+
+func TestSSHHostEntries(t *testing.T) {
+	instances := []aws.Instance{
+		{ID: "i-0aaaaaaaa11111111", Name: "Web API"},
+		{ID: "i-0bbbbbbbb22222222", Name: "web api"},
+		{ID: "i-0cccccccc33333333", Name: "bastion"},
+		{ID: "i-0dddddddd44444444"},
+	}
+	var got []string
+	for _, m := range regexp.MustCompile(`(?m)^Host (.+)$`).FindAllStringSubmatch(sshHostEntries("prod", instances), -1) {
+		got = append(got, m[1])
+	}
+	want := []string{
+		"prod.web-api-11111111 i-0aaaaaaaa11111111",
+		"prod.web-api-22222222 i-0bbbbbbbb22222222",
+		"prod.bastion i-0cccccccc33333333",
+		"i-0dddddddd44444444",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Host lines = %q, want %q", got, want)
+	}
+}
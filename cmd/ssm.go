@@ -1,18 +1,31 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/ilkerispir/aws-ssm-connect/internal/audit"
 	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+	"github.com/ilkerispir/aws-ssm-connect/internal/ui"
 	"github.com/manifoldco/promptui"
 )
 
-// StartSSMSession starts a standard SSM shell session to a selected EC2 instance
-func StartSSMSession(profile string) error {
-	instances, err := aws.FetchInstances(profile)
+const (
+	// defaultShellDocument starts the shell configured in Session Manager preferences
+	defaultShellDocument = "SSM-SessionManagerRunShell"
+	// commandDocument runs a single interactive command
+	commandDocument = "AWS-StartInteractiveCommand"
+)
+
+// StartSSMSession starts an SSM shell session to a selected EC2 instance. The document, run-as user
+// and command come from the settings file, overridden by non-empty fields of override; with
+// pickDocument the document is chosen from the account's Session Manager documents.
+func StartSSMSession(profile string, refresh bool, override settings.Session, pickDocument bool) error {
+	instances, err := aws.LoadInstances(profile, refresh)
 	if err != nil {
 		return fmt.Errorf("fetch instances failed: %w", err)
 	}
@@ -22,7 +35,7 @@ func StartSSMSession(profile string) error {
 
 	options := make([]string, len(instances))
 	for i, inst := range instances {
-		options[i] = fmt.Sprintf("🖥️  %s (%s)", inst.Name, inst.ID)
+		options[i] = ui.FormatInstanceLabel(inst)
 	}
 
 	prompt := promptui.Select{
@@ -40,19 +53,105 @@ func StartSSMSession(profile string) error {
 
 	instance := instances[idx]
 
-	fmt.Printf("\n✅ Starting SSM shell session to: %s (%s)\n\n", instance.Name, instance.ID)
+	prefs, err := settings.Load()
+	if err != nil {
+		return fmt.Errorf("load settings failed: %w", err)
+	}
+	session := prefs.SessionFor(profile, instance.ID, instance.Name).Merge(override)
+	if pickDocument {
+		if session.Document, err = promptSessionDocument(profile); err != nil {
+			return err
+		}
+	}
 
-	cmd := exec.Command(
-		"aws", "ssm", "start-session",
+	doc := aws.SessionDocument{Name: session.Document}
+	if doc.Name == "" {
+		doc.Name = defaultShellDocument
+	}
+	if session.RunAs != "" || session.Command != "" {
+		if doc, err = aws.FetchSessionDocument(profile, doc.Name); err != nil {
+			return err
+		}
+	}
+	document, parameters, err := sessionDocument(session, doc)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✅ Starting SSM shell session to: %s (%s) with %s\n\n", instance.Name, instance.ID, document)
+
+	args := []string{
+		"ssm", "start-session",
 		"--profile", profile,
 		"--target", instance.ID,
-		"--document-name", "AWS-StartInteractiveCommand",
-		"--parameters", "command=bash",
-	)
+		"--document-name", document,
+	}
+	if parameters != "" {
+		args = append(args, "--parameters", parameters)
+	}
+	cmd := exec.Command("aws", args...)
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
-	return cmd.Run()
+	return runRecorded(cmd, audit.Record{
+		Profile:    profile,
+		Instance:   instance.Name,
+		InstanceID: instance.ID,
+		Target:     document,
+	})
+}
+
+// sessionDocument picks the document and its parameters for a session from doc, the configured
+// shell document. A run-as user comes from the document's Run As preference, or a sudo command
+// when it takes a command parameter. A command needs such a parameter; only the default shell
+// falls back to AWS-StartInteractiveCommand, so an org document is never bypassed.
+func sessionDocument(s settings.Session, doc aws.SessionDocument) (string, string, error) {
+	command := s.Command
+	if s.RunAs != "" && !(doc.RunAsEnabled && doc.RunAsUser == s.RunAs) {
+		if !doc.Takes("command") {
+			return "", "", fmt.Errorf("%s does not run sessions as %s: set Run As to %s in its preferences or use a document with a command parameter", doc.Name, s.RunAs, s.RunAs)
+		}
+		command = strings.TrimSpace("sudo -i -u " + s.RunAs + " " + command)
+	}
+	if command == "" {
+		return doc.Name, "", nil
+	}
+
+	document := doc.Name
+	if !doc.Takes("command") {
+		if document != defaultShellDocument {
+			return "", "", fmt.Errorf("%s has no command parameter, so it cannot run %q", document, command)
+		}
+		document = commandDocument
+	}
+	// JSON keeps commas and quotes in the command intact
+	params, err := json.Marshal(map[string][]string{"command": {command}})
+	if err != nil {
+		return "", "", err
+	}
+	return document, string(params), nil
+}
+
+// promptSessionDocument lets the user choose between the AWS shell documents and the account's own
+func promptSessionDocument(profile string) (string, error) {
+	custom, err := aws.FetchSessionDocuments(profile)
+	if err != nil {
+		return "", err
+	}
+	documents := append([]string{defaultShellDocument, commandDocument}, custom...)
+
+	prompt := promptui.Select{
+		Label: "Select session document",
+		Items: documents,
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(strings.ToLower(documents[index]), strings.ToLower(input))
+		},
+	}
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("prompt failed: %w", err)
+	}
+	return documents[idx], nil
 }
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+func TestSessionDocument(t *testing.T) {
+	shell := aws.SessionDocument{Name: defaultShellDocument}
+	shellAsOps := aws.SessionDocument{Name: defaultShellDocument, RunAsEnabled: true, RunAsUser: "ops"}
+	org := aws.SessionDocument{Name: "Org-SessionManagerRunShell"}
+	orgAsOps := aws.SessionDocument{Name: "Org-SessionManagerRunShell", RunAsEnabled: true, RunAsUser: "ops"}
+	orgCommand := aws.SessionDocument{Name: "Org-ShellCommand", Parameters: []string{"command"}}
+
+	tests := []struct {
+		name       string
+		session    settings.Session
+		doc        aws.SessionDocument
+		document   string
+		parameters string
+		wantErr    bool
+	}{
+		{name: "default shell", doc: shell, document: defaultShellDocument},
+		{name: "org shell", session: settings.Session{Document: "Org-SessionManagerRunShell"}, doc: org, document: "Org-SessionManagerRunShell"},
+		{name: "run as from the org preferences", session: settings.Session{Document: "Org-SessionManagerRunShell", RunAs: "ops"}, doc: orgAsOps, document: "Org-SessionManagerRunShell"},
+		{name: "org shell cannot run as another user", session: settings.Session{Document: "Org-SessionManagerRunShell", RunAs: "admin"}, doc: orgAsOps, wantErr: true},
+		{name: "org shell without run as", session: settings.Session{Document: "Org-SessionManagerRunShell", RunAs: "ops"}, doc: org, wantErr: true},
+		{name: "org shell cannot run a command", session: settings.Session{Document: "Org-SessionManagerRunShell", Command: "sh"}, doc: orgAsOps, wantErr: true},
+		{name: "run as from the account preferences", session: settings.Session{RunAs: "ops"}, doc: shellAsOps, document: defaultShellDocument},
+		{name: "default shell without run as", session: settings.Session{RunAs: "ops"}, doc: shell, wantErr: true},
+		{name: "command on the default shell", session: settings.Session{Command: "sh"}, doc: shell, document: commandDocument, parameters: `{"command":["sh"]}`},
+		{name: "run as through a command document", session: settings.Session{Document: "Org-ShellCommand", RunAs: "ops", Command: "bash"}, doc: orgCommand, document: "Org-ShellCommand", parameters: `{"command":["sudo -i -u ops bash"]}`},
+		{name: "command keeps quotes", session: settings.Session{Document: "Org-ShellCommand", Command: `psql -c "select 1, 2"`}, doc: orgCommand, document: "Org-ShellCommand", parameters: `{"command":["psql -c \"select 1, 2\""]}`},
+	}
+	for _, tt := range tests {
+		document, parameters, err := sessionDocument(tt.session, tt.doc)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if document != tt.document || parameters != tt.parameters {
+			t.Errorf("%s: got %q %s, want %q %s", tt.name, document, parameters, tt.document, tt.parameters)
+		}
+	}
+}
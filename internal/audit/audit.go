@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+// Events recorded for a session
+const (
+	EventStart = "start"
+	EventStop  = "stop"
+)
+
+// Record is one line of the audit log
+type Record struct {
+	Time       time.Time `json:"time"`
+	Event      string    `json:"event"`
+	Kind       string    `json:"kind"`
+	Caller     string    `json:"caller,omitempty"`
+	Profile    string    `json:"profile"`
+	Instance   string    `json:"instance,omitempty"`
+	InstanceID string    `json:"instance_id,omitempty"`
+	Target     string    `json:"target,omitempty"`
+	LocalPort  string    `json:"local_port,omitempty"`
+	PID        int       `json:"pid,omitempty"`
+	Started    time.Time `json:"started,omitzero"`
+	Stopped    time.Time `json:"stopped,omitzero"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+var logPath = filepath.Join(os.Getenv("HOME"), ".aws-ssm-connect", "audit.jsonl")
+
+// webhookTimeout bounds how long a webhook may delay the command being audited
+const webhookTimeout = 5 * time.Second
+
+// Log appends rec to the audit log and sends it to the configured webhook.
+// Failures are reported on stderr rather than stopping the session.
+func Log(rec Record) {
+	if rec.Time.IsZero() {
+		rec.Time = time.Now().UTC()
+	}
+	if err := Append(logPath, rec); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ Audit log: %v\n", err)
+	}
+
+	prefs, err := settings.Load()
+	if err != nil || prefs.Audit.Webhook == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	if err := Post(ctx, prefs.Audit.Webhook, prefs.Audit.Headers, rec); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ Audit webhook: %v\n", err)
+	}
+}
+
+// Append writes rec as a single JSON line at the end of the log at path
+func Append(path string, rec Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Post sends rec as JSON to url, failing on any non-2xx response
+func Post(ctx context.Context, url string, headers map[string]string, rec Record) error {
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("post failed: %s", resp.Status)
+	}
+	return nil
+}
+
+// Caller returns the STS caller ARN for the profile, or "" if it cannot be determined
+func Caller(profile string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	arn, err := aws.CallerARN(ctx, profile)
+	if err != nil {
+		return ""
+	}
+	return arn
+}
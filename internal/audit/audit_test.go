@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	records := []Record{
+		{Time: start, Event: EventStart, Kind: "port-forward", Profile: "prod", InstanceID: "i-0abc", Target: "db:5432", LocalPort: "5432", PID: 42, Started: start},
+		{Time: start.Add(time.Hour), Event: EventStop, Kind: "port-forward", Profile: "prod", PID: 42, Started: start, Stopped: start.Add(time.Hour), Reason: "killed"},
+	}
+	for _, rec := range records {
+		if err := Append(path, rec); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var got []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line %q is not a record: %v", scanner.Text(), err)
+		}
+		got = append(got, rec)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Errorf("records = %+v, want %+v", got, records)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("log mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestPost(t *testing.T) {
+	var got Record
+	var auth, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	rec := Record{Event: EventStart, Kind: "shell", Caller: "arn:aws:sts::123456789012:assumed-role/dev/alice", Profile: "dev", InstanceID: "i-0abc"}
+	if err := Post(context.Background(), srv.URL, map[string]string{"Authorization": "Bearer token"}, rec); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if !reflect.DeepEqual(got, rec) {
+		t.Errorf("webhook got %+v, want %+v", got, rec)
+	}
+	if auth != "Bearer token" || contentType != "application/json" {
+		t.Errorf("headers = %q, %q", auth, contentType)
+	}
+}
+
+func TestPostRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	if err := Post(context.Background(), srv.URL, nil, Record{Event: EventStop}); err == nil {
+		t.Error("Post succeeded, want error for 403")
+	}
+}
@@ -0,0 +1,152 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/ilkerispir/aws-ssm-connect/internal/cache"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+// cacheTTL is how long discovery results are served without a background refresh
+const cacheTTL = 15 * time.Minute
+
+var refreshes sync.WaitGroup
+
+// LoadInstances returns the SSM-managed instances for the profile, served from the
+// on-disk cache when possible. Stale entries are returned immediately and refreshed
+// in the background; refresh forces a fresh fetch.
+func LoadInstances(profile string, refresh bool) ([]Instance, error) {
+	instances, _, err := loadCached(profile, "instances", refresh,
+		func(profile string) ([]Instance, []Warning, error) {
+			instances, err := FetchInstances(profile)
+			return instances, nil, err
+		},
+		func(profile string) ([]Instance, []Warning, error) {
+			instances, err := fetchInstances(profile)
+			return instances, nil, err
+		})
+	return instances, err
+}
+
+// LoadTargets returns the tunnel targets for the profile, using the same caching rules as LoadInstances.
+// Results with warnings are never cached, so cache hits are always complete.
+func LoadTargets(profile string, refresh bool) ([]Target, []Warning, error) {
+	return loadCached(profile, "targets", refresh, FetchTargets, FetchTargets)
+}
+
+// LoadJumpHosts returns the SSM-managed instances followed by the running exec-enabled ECS containers,
+// unless the "ecs" provider is switched off. Tasks come and go, so they are always fetched fresh;
+// a failed ECS listing is reported as a warning.
+func LoadJumpHosts(profile string, refresh bool) ([]Instance, []Warning, error) {
+	instances, err := LoadInstances(profile, refresh)
+	if err != nil {
+		return nil, nil, err
+	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("load settings failed: %w", err)
+	}
+	if !prefs.ProviderEnabled("ecs") {
+		return instances, nil, nil
+	}
+
+	tasks, err := FetchTasks(profile)
+	for _, t := range tasks {
+		instances = append(instances, t.Instance())
+	}
+	return instances, warningsFrom("ecs", err), nil
+}
+
+// WaitForRefresh blocks until all background cache refreshes have finished
+func WaitForRefresh() {
+	refreshes.Wait()
+}
+
+// loadCached serves kind from the cache, calling fetch on a miss and background to refresh stale entries.
+// background must not prompt: it runs while the caller's prompts own the terminal.
+func loadCached[T any](profile, kind string, refresh bool, fetch, background func(string) ([]T, []Warning, error)) ([]T, []Warning, error) {
+	key, err := cacheKey(profile, kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !refresh {
+		var cached []T
+		found, fresh := cache.Load(key, cacheTTL, &cached)
+		if found {
+			if !fresh {
+				refreshes.Add(1)
+				go func() {
+					defer refreshes.Done()
+					items, warnings, err := background(profile)
+					if isExpiredToken(err) {
+						// the next foreground fetch logs in; keep the cache until then
+						return
+					}
+					if err != nil {
+						log.Printf("⚠️ background refresh of %s failed: %v", kind, err)
+						return
+					}
+					// a partial result must not replace a complete one
+					if len(warnings) > 0 {
+						return
+					}
+					_ = cache.Save(key, items)
+				}()
+			}
+			return cached, nil, nil
+		}
+	}
+
+	items, warnings, err := fetch(profile)
+	if err != nil {
+		return nil, nil, err
+	}
+	// partial results are not cached, so the failed providers are retried and reported next time
+	if len(warnings) == 0 {
+		if err := cache.Save(key, items); err != nil {
+			log.Printf("⚠️ failed to write %s cache: %v", kind, err)
+		}
+	}
+	return items, warnings, nil
+}
+
+// cacheKey scopes cache entries by profile, the region the profile resolves to, any endpoint overrides
+// and the providers switched off
+func cacheKey(profile, kind string) (string, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return "", fmt.Errorf("load config failed: %w", err)
+	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return "", fmt.Errorf("load settings failed: %w", err)
+	}
+	return fmt.Sprintf("%s_%s_%s%s%s", profile, cfg.Region, kind, endpointsTag(prefs), providersTag(prefs)), nil
+}
+
+// providersTag fingerprints the disabled providers so that switching one on or off
+// takes effect immediately instead of after the cache expires
+func providersTag(s *settings.Settings) string {
+	var disabled []string
+	for name, enabled := range s.Providers {
+		if !enabled {
+			disabled = append(disabled, name)
+		}
+	}
+	if len(disabled) == 0 {
+		return ""
+	}
+	sort.Strings(disabled)
+	h := fnv.New32a()
+	fmt.Fprint(h, strings.Join(disabled, ","))
+	return fmt.Sprintf("_providers-%08x", h.Sum32())
+}
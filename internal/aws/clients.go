@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/kafka"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+// RDSAPI is the subset of the RDS client used by discovery
+type RDSAPI interface {
+	rds.DescribeDBClustersAPIClient
+	rds.DescribeDBSubnetGroupsAPIClient
+	rds.DescribeDBInstancesAPIClient
+	rds.DescribeDBClusterEndpointsAPIClient
+	rds.DescribeDBProxiesAPIClient
+	rds.DescribeDBProxyEndpointsAPIClient
+}
+
+// ElastiCacheAPI is the subset of the ElastiCache client used by discovery
+type ElastiCacheAPI interface {
+	elasticache.DescribeCacheSubnetGroupsAPIClient
+	elasticache.DescribeCacheClustersAPIClient
+	elasticache.DescribeReplicationGroupsAPIClient
+	elasticache.DescribeServerlessCachesAPIClient
+}
+
+// MemoryDBAPI is the subset of the MemoryDB client used by discovery
+type MemoryDBAPI interface {
+	memorydb.DescribeSubnetGroupsAPIClient
+	memorydb.DescribeClustersAPIClient
+}
+
+// RedshiftAPI is the subset of the Redshift client used by discovery
+type RedshiftAPI interface {
+	redshift.DescribeClustersAPIClient
+}
+
+// OpenSearchAPI is the subset of the OpenSearch client used by discovery
+type OpenSearchAPI interface {
+	ListDomainNames(ctx context.Context, params *opensearch.ListDomainNamesInput, optFns ...func(*opensearch.Options)) (*opensearch.ListDomainNamesOutput, error)
+	DescribeDomains(ctx context.Context, params *opensearch.DescribeDomainsInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeDomainsOutput, error)
+}
+
+// KafkaAPI is the subset of the MSK client used by discovery
+type KafkaAPI interface {
+	kafka.ListClustersV2APIClient
+	GetBootstrapBrokers(ctx context.Context, params *kafka.GetBootstrapBrokersInput, optFns ...func(*kafka.Options)) (*kafka.GetBootstrapBrokersOutput, error)
+}
+
+// EC2API is the subset of the EC2 client used by discovery
+type EC2API interface {
+	ec2.DescribeSubnetsAPIClient
+	ec2.DescribeInstancesAPIClient
+}
+
+// ECSAPI is the subset of the ECS client used by discovery
+type ECSAPI interface {
+	ecs.ListClustersAPIClient
+	ecs.ListTasksAPIClient
+	DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+}
+
+// SSMAPI is the subset of the SSM client used by discovery
+type SSMAPI interface {
+	ssm.DescribeInstanceInformationAPIClient
+}
+
+// Discovery carries the SDK clients that discovery providers call.
+// Tests build it with fakes; NewDiscovery wires the real clients.
+type Discovery struct {
+	RDS         RDSAPI
+	ElastiCache ElastiCacheAPI
+	MemoryDB    MemoryDBAPI
+	Redshift    RedshiftAPI
+	OpenSearch  OpenSearchAPI
+	Kafka       KafkaAPI
+	EC2         EC2API
+	SSM         SSMAPI
+	ECS         ECSAPI
+}
+
+// NewDiscovery builds a discovery context backed by SDK clients for cfg,
+// applying any endpoint overrides from the environment or settings
+func NewDiscovery(cfg aws.Config, s *settings.Settings) *Discovery {
+	return &Discovery{
+		RDS:         rds.NewFromConfig(cfg, func(o *rds.Options) { overrideEndpoint(&o.BaseEndpoint, s, "rds") }),
+		ElastiCache: elasticache.NewFromConfig(cfg, func(o *elasticache.Options) { overrideEndpoint(&o.BaseEndpoint, s, "elasticache") }),
+		MemoryDB:    memorydb.NewFromConfig(cfg, func(o *memorydb.Options) { overrideEndpoint(&o.BaseEndpoint, s, "memorydb") }),
+		Redshift:    redshift.NewFromConfig(cfg, func(o *redshift.Options) { overrideEndpoint(&o.BaseEndpoint, s, "redshift") }),
+		OpenSearch:  opensearch.NewFromConfig(cfg, func(o *opensearch.Options) { overrideEndpoint(&o.BaseEndpoint, s, "opensearch") }),
+		Kafka:       kafka.NewFromConfig(cfg, func(o *kafka.Options) { overrideEndpoint(&o.BaseEndpoint, s, "kafka") }),
+		EC2:         ec2.NewFromConfig(cfg, func(o *ec2.Options) { overrideEndpoint(&o.BaseEndpoint, s, "ec2") }),
+		SSM:         ssm.NewFromConfig(cfg, func(o *ssm.Options) { overrideEndpoint(&o.BaseEndpoint, s, "ssm") }),
+		ECS:         ecs.NewFromConfig(cfg, func(o *ecs.Options) { overrideEndpoint(&o.BaseEndpoint, s, "ecs") }),
+	}
+}
+
+// NewSSMClient returns an SSM client for the profile that honours endpoint overrides
+func NewSSMClient(profile string) (*ssm.Client, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return nil, fmt.Errorf("load config failed: %w", err)
+	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load settings failed: %w", err)
+	}
+	return ssm.NewFromConfig(cfg, func(o *ssm.Options) { overrideEndpoint(&o.BaseEndpoint, prefs, "ssm") }), nil
+}
@@ -0,0 +1,281 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// commandPollInterval is how often a running command's status is checked
+var commandPollInterval = time.Second
+
+// CommandAPI is the subset of the SSM client used to run shell commands on instances
+type CommandAPI interface {
+	SendCommand(ctx context.Context, params *ssm.SendCommandInput, optFns ...func(*ssm.Options)) (*ssm.SendCommandOutput, error)
+	GetCommandInvocation(ctx context.Context, params *ssm.GetCommandInvocationInput, optFns ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error)
+	CancelCommand(ctx context.Context, params *ssm.CancelCommandInput, optFns ...func(*ssm.Options)) (*ssm.CancelCommandOutput, error)
+}
+
+// CommandResult is the outcome of a shell command run on one instance
+type CommandResult struct {
+	Status   string
+	ExitCode int32
+	Stdout   string
+	Stderr   string
+}
+
+// RunShellCommand runs commands on an instance with AWS-RunShellScript and waits for them to finish.
+// A command that runs but exits non-zero is reported through the result, not as an error.
+func RunShellCommand(ctx context.Context, client CommandAPI, instanceID string, commands []string) (CommandResult, error) {
+	sent, err := client.SendCommand(ctx, &ssm.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		InstanceIds:  []string{instanceID},
+		Parameters:   map[string][]string{"commands": commands},
+	})
+	if err != nil {
+		return CommandResult{}, fmt.Errorf("send command failed: %w", err)
+	}
+	return waitForCommand(ctx, client, aws.ToString(sent.Command.CommandId), instanceID)
+}
+
+// waitForCommand polls a command invocation until it reaches a terminal status
+func waitForCommand(ctx context.Context, client CommandAPI, commandID, instanceID string) (CommandResult, error) {
+	for {
+		out, err := client.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(instanceID),
+		})
+		var notYet *ssmtypes.InvocationDoesNotExist
+		switch {
+		case errors.As(err, &notYet):
+			// the invocation is not visible right after SendCommand returns
+		case err != nil:
+			return CommandResult{}, fmt.Errorf("get command invocation failed: %w", err)
+		default:
+			switch out.Status {
+			case ssmtypes.CommandInvocationStatusPending, ssmtypes.CommandInvocationStatusInProgress,
+				ssmtypes.CommandInvocationStatusDelayed, ssmtypes.CommandInvocationStatusCancelling:
+			default:
+				return CommandResult{
+					Status:   string(out.Status),
+					ExitCode: out.ResponseCode,
+					Stdout:   aws.ToString(out.StandardOutputContent),
+					Stderr:   aws.ToString(out.StandardErrorContent),
+				}, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return CommandResult{}, ctx.Err()
+		case <-time.After(commandPollInterval):
+		}
+	}
+}
+
+// sendCommandBatch is the most instance IDs SendCommand accepts per call
+const sendCommandBatch = 50
+
+// FleetOptions bounds a command run across many instances.
+// Both values use SSM's syntax: an absolute number or a percentage such as "10%".
+type FleetOptions struct {
+	MaxConcurrency string
+	MaxErrors      string
+}
+
+// RunFleetCommand runs commands on every instance with AWS-RunShellScript and waits for all of them.
+// Instances above the 50 IDs a single command accepts are sent in further commands, all sent up front
+// and each given its share of the concurrency limit, so the limit holds for the whole fleet. SSM stops
+// each command at the error threshold; failures are also counted across commands, and once the fleet
+// crosses the threshold every command is cancelled, as they are when ctx ends. Results are keyed by instance ID.
+func RunFleetCommand(ctx context.Context, client CommandAPI, instanceIDs, commands []string, opts FleetOptions) (map[string]CommandResult, error) {
+	maxErrors, err := fleetThreshold(opts.MaxErrors, len(instanceIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	type invocation struct{ commandID, instanceID string }
+	var (
+		invocations []invocation
+		commandIDs  []string
+	)
+	for start := 0; start < len(instanceIDs); start += sendCommandBatch {
+		batch := instanceIDs[start:min(start+sendCommandBatch, len(instanceIDs))]
+		input := &ssm.SendCommandInput{
+			DocumentName: aws.String("AWS-RunShellScript"),
+			InstanceIds:  batch,
+			Parameters:   map[string][]string{"commands": commands},
+		}
+		if opts.MaxConcurrency != "" {
+			concurrency, err := batchConcurrency(opts.MaxConcurrency, len(batch), len(instanceIDs))
+			if err != nil {
+				return nil, err
+			}
+			input.MaxConcurrency = aws.String(concurrency)
+		}
+		if opts.MaxErrors != "" {
+			input.MaxErrors = aws.String(opts.MaxErrors)
+		}
+		sent, err := client.SendCommand(ctx, input)
+		if err != nil {
+			cancelCommands(client, commandIDs)
+			return nil, fmt.Errorf("send command failed: %w", err)
+		}
+		commandID := aws.ToString(sent.Command.CommandId)
+		commandIDs = append(commandIDs, commandID)
+		for _, id := range batch {
+			invocations = append(invocations, invocation{commandID, id})
+		}
+	}
+
+	var (
+		mu        sync.Mutex
+		results   = map[string]CommandResult{}
+		failures  int
+		cancelled bool
+		eg        errgroup.Group
+	)
+	// polling every instance at once would be throttled
+	eg.SetLimit(10)
+	for _, inv := range invocations {
+		eg.Go(func() error {
+			result, err := waitForCommand(ctx, client, inv.commandID, inv.instanceID)
+			if err != nil {
+				result = CommandResult{Status: "Error", ExitCode: -1, Stderr: err.Error()}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[inv.instanceID] = result
+			if result.Status != string(ssmtypes.CommandInvocationStatusSuccess) {
+				failures++
+			}
+			if opts.MaxErrors != "" && failures > maxErrors && !cancelled {
+				cancelled = true
+				cancelCommands(client, commandIDs)
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+	// don't leave commands running that nobody is waiting for
+	if ctx.Err() != nil && !cancelled {
+		cancelCommands(client, commandIDs)
+	}
+	return results, nil
+}
+
+// fleetThreshold turns an SSM limit into a count of instances out of total
+func fleetThreshold(limit string, total int) (int, error) {
+	if limit == "" {
+		return total, nil
+	}
+	if pct, ok := strings.CutSuffix(limit, "%"); ok {
+		n, err := strconv.Atoi(pct)
+		if err != nil || n < 0 || n > 100 {
+			return 0, fmt.Errorf("invalid limit %q", limit)
+		}
+		return total * n / 100, nil
+	}
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid limit %q", limit)
+	}
+	return n, nil
+}
+
+// batchConcurrency gives a command of size instances its share of a fleet-wide concurrency limit.
+// Percentages already scale with the batch; absolute limits are split, with at least one per batch.
+func batchConcurrency(limit string, size, total int) (string, error) {
+	if strings.HasSuffix(limit, "%") {
+		return limit, nil
+	}
+	n, err := fleetThreshold(limit, total)
+	if err != nil {
+		return "", err
+	}
+	return strconv.Itoa(max(1, n*size/total)), nil
+}
+
+// cancelCommands stops commands still running on any instance, even after ctx has expired
+func cancelCommands(client CommandAPI, commandIDs []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, id := range commandIDs {
+		_, _ = client.CancelCommand(ctx, &ssm.CancelCommandInput{CommandId: aws.String(id)})
+	}
+}
+
+// hostnamePattern restricts names passed to the instance's shell
+var hostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`)
+
+// ResolveOnInstance looks a hostname up from the instance, which sees private hosted zones
+// and VPC DNS that the local machine may not. It returns no addresses if the name does not resolve.
+func ResolveOnInstance(ctx context.Context, client CommandAPI, instanceID, host string) ([]string, error) {
+	if !hostnamePattern.MatchString(host) {
+		return nil, fmt.Errorf("invalid hostname %q", host)
+	}
+	result, err := RunShellCommand(ctx, client, instanceID, []string{"getent ahosts " + host})
+	if err != nil {
+		return nil, err
+	}
+	// getent exits 2 when the name is unknown
+	if result.Status != string(ssmtypes.CommandInvocationStatusSuccess) {
+		if result.ExitCode == 2 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lookup on %s %s: %s", instanceID, strings.ToLower(result.Status), strings.TrimSpace(result.Stderr))
+	}
+
+	var addrs []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(result.Stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		addrs = append(addrs, fields[0])
+	}
+	return addrs, nil
+}
+
+// osUserPattern restricts user names passed to the instance's shell
+var osUserPattern = regexp.MustCompile(`^[a-z_][a-z0-9_-]*[$]?$`)
+
+// RemoteSHA256 returns the SHA-256 of a file on the instance. Relative paths are taken from
+// osUser's home directory, and an empty or directory path refers to the file named name inside it, as with scp.
+func RemoteSHA256(ctx context.Context, client CommandAPI, instanceID, osUser, path, name string) (string, error) {
+	if !osUserPattern.MatchString(osUser) {
+		return "", fmt.Errorf("invalid user name %q", osUser)
+	}
+	if path == "" {
+		// "i-abc:" is the home directory
+		path = "."
+	}
+	script := fmt.Sprintf(`cd ~%s && f=%s && if [ -d "$f" ]; then f="$f/"%s; fi && sha256sum -- "$f"`,
+		osUser, shellQuote(path), shellQuote(name))
+	result, err := RunShellCommand(ctx, client, instanceID, []string{script})
+	if err != nil {
+		return "", err
+	}
+	if result.Status != string(ssmtypes.CommandInvocationStatusSuccess) {
+		return "", fmt.Errorf("sha256sum on %s %s: %s", instanceID, strings.ToLower(result.Status), strings.TrimSpace(result.Stderr))
+	}
+	sum, _, _ := strings.Cut(strings.TrimSpace(result.Stdout), " ")
+	return sum, nil
+}
+
+// shellQuote quotes s as a single POSIX shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,204 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestResolveOnInstance(t *testing.T) {
+	commandPollInterval = 0
+
+	tests := []struct {
+		name        string
+		invocations []*ssm.GetCommandInvocationOutput
+		want        []string
+		wantErr     bool
+	}{
+		{
+			name: "resolves after polling",
+			invocations: []*ssm.GetCommandInvocationOutput{
+				nil,
+				{Status: ssmtypes.CommandInvocationStatusInProgress},
+				{
+					Status: ssmtypes.CommandInvocationStatusSuccess,
+					StandardOutputContent: aws.String("10.0.1.5      STREAM internal-api.corp\n" +
+						"10.0.1.5      DGRAM\n10.0.2.7      STREAM\n"),
+				},
+			},
+			want: []string{"10.0.1.5", "10.0.2.7"},
+		},
+		{
+			name:        "unknown name",
+			invocations: []*ssm.GetCommandInvocationOutput{{Status: ssmtypes.CommandInvocationStatusFailed, ResponseCode: 2}},
+		},
+		{
+			name:        "agent failure",
+			invocations: []*ssm.GetCommandInvocationOutput{{Status: ssmtypes.CommandInvocationStatusTimedOut, ResponseCode: -1}},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeCommands{invocations: tt.invocations}
+			got, err := ResolveOnInstance(context.Background(), client, "i-abc", "internal-api.corp")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("addrs = %v, want %v", got, tt.want)
+			}
+			if cmds := client.sent[0].Parameters["commands"]; !reflect.DeepEqual(cmds, []string{"getent ahosts internal-api.corp"}) {
+				t.Errorf("commands = %v", cmds)
+			}
+		})
+	}
+}
+
+func TestResolveOnInstanceRejectsShellInput(t *testing.T) {
+	client := &fakeCommands{}
+	if _, err := ResolveOnInstance(context.Background(), client, "i-abc", "db; rm -rf /"); err == nil {
+		t.Fatal("expected an error for a hostname with shell metacharacters")
+	}
+	if len(client.sent) != 0 {
+		t.Errorf("SendCommand called %d times, want 0", len(client.sent))
+	}
+}
+
+func TestRunFleetCommand(t *testing.T) {
+	commandPollInterval = 0
+
+	var ids []string
+	client := &fakeCommands{byInstance: map[string]*ssm.GetCommandInvocationOutput{}}
+	for i := 0; i < 120; i++ {
+		id := fmt.Sprintf("i-%03d", i)
+		ids = append(ids, id)
+		client.byInstance[id] = &ssm.GetCommandInvocationOutput{
+			Status:                ssmtypes.CommandInvocationStatusSuccess,
+			StandardOutputContent: aws.String("ok " + id),
+		}
+	}
+	client.byInstance["i-007"] = &ssm.GetCommandInvocationOutput{Status: ssmtypes.CommandInvocationStatusFailed, ResponseCode: 1}
+
+	results, err := RunFleetCommand(context.Background(), client, ids, []string{"df -h"}, FleetOptions{MaxConcurrency: "10%", MaxErrors: "2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 120 {
+		t.Fatalf("got %d results, want 120", len(results))
+	}
+	if r := results["i-042"]; r.Status != "Success" || r.Stdout != "ok i-042" {
+		t.Errorf("i-042 = %+v", r)
+	}
+	if r := results["i-007"]; r.Status != "Failed" || r.ExitCode != 1 {
+		t.Errorf("i-007 = %+v", r)
+	}
+
+	var batches []int
+	for _, in := range client.sent {
+		batches = append(batches, len(in.InstanceIds))
+		if aws.ToString(in.MaxConcurrency) != "10%" || aws.ToString(in.MaxErrors) != "2" {
+			t.Errorf("limits = %v/%v, want 10%%/2", aws.ToString(in.MaxConcurrency), aws.ToString(in.MaxErrors))
+		}
+	}
+	if !reflect.DeepEqual(batches, []int{50, 50, 20}) {
+		t.Errorf("SendCommand batches = %v, want [50 50 20]", batches)
+	}
+	if client.sentAtFirstPoll != 3 {
+		t.Errorf("%d commands sent before polling started, want all 3", client.sentAtFirstPoll)
+	}
+	if len(client.cancelled) != 0 {
+		t.Errorf("cancelled %v below the error threshold", client.cancelled)
+	}
+}
+
+func TestRunFleetCommandFleetWideLimits(t *testing.T) {
+	commandPollInterval = 0
+
+	var ids []string
+	client := &fakeCommands{byInstance: map[string]*ssm.GetCommandInvocationOutput{}}
+	for i := 0; i < 120; i++ {
+		id := fmt.Sprintf("i-%03d", i)
+		ids = append(ids, id)
+		client.byInstance[id] = &ssm.GetCommandInvocationOutput{Status: ssmtypes.CommandInvocationStatusSuccess}
+	}
+	// one failure in each of the first two commands: within each command's limit, over the fleet's
+	client.byInstance["i-010"] = &ssm.GetCommandInvocationOutput{Status: ssmtypes.CommandInvocationStatusFailed, ResponseCode: 1}
+	client.byInstance["i-060"] = &ssm.GetCommandInvocationOutput{Status: ssmtypes.CommandInvocationStatusFailed, ResponseCode: 1}
+
+	if _, err := RunFleetCommand(context.Background(), client, ids, []string{"uptime"}, FleetOptions{MaxConcurrency: "24", MaxErrors: "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var concurrency []string
+	for _, in := range client.sent {
+		concurrency = append(concurrency, aws.ToString(in.MaxConcurrency))
+	}
+	if !reflect.DeepEqual(concurrency, []string{"10", "10", "4"}) {
+		t.Errorf("per-command concurrency = %v, want [10 10 4]", concurrency)
+	}
+	if !reflect.DeepEqual(client.cancelled, []string{"cmd-1", "cmd-2", "cmd-3"}) {
+		t.Errorf("cancelled = %v, want every command", client.cancelled)
+	}
+}
+
+func TestFleetThreshold(t *testing.T) {
+	tests := []struct {
+		limit string
+		total int
+		want  int
+	}{
+		{"0", 120, 0},
+		{"5", 120, 5},
+		{"10%", 120, 12},
+		{"10%", 5, 0},
+		{"", 7, 7},
+	}
+	for _, tt := range tests {
+		if got, err := fleetThreshold(tt.limit, tt.total); err != nil || got != tt.want {
+			t.Errorf("fleetThreshold(%q, %d) = %d, %v; want %d", tt.limit, tt.total, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"abc", "-1", "150%"} {
+		if _, err := fleetThreshold(bad, 10); err == nil {
+			t.Errorf("fleetThreshold(%q) accepted", bad)
+		}
+	}
+}
+
+func TestRemoteSHA256(t *testing.T) {
+	commandPollInterval = 0
+	client := &fakeCommands{invocations: []*ssm.GetCommandInvocationOutput{{
+		Status:                ssmtypes.CommandInvocationStatusSuccess,
+		StandardOutputContent: aws.String("9f86d081884c7d65  /home/ec2-user/it's here/heap.hprof\n"),
+	}}}
+
+	sum, err := RemoteSHA256(context.Background(), client, "i-abc", "ec2-user", "it's here/", "heap.hprof")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sum != "9f86d081884c7d65" {
+		t.Errorf("sum = %q", sum)
+	}
+	want := `cd ~ec2-user && f='it'\''s here/' && if [ -d "$f" ]; then f="$f/"'heap.hprof'; fi && sha256sum -- "$f"`
+	if got := client.sent[0].Parameters["commands"][0]; got != want {
+		t.Errorf("script =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := RemoteSHA256(context.Background(), client, "i-abc", "ec2-user", "", "app.conf"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = `cd ~ec2-user && f='.' && if [ -d "$f" ]; then f="$f/"'app.conf'; fi && sha256sum -- "$f"`
+	if got := client.sent[1].Parameters["commands"][0]; got != want {
+		t.Errorf("home directory script =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := RemoteSHA256(context.Background(), client, "i-abc", "root; reboot", "x", "x"); err == nil {
+		t.Error("expected an error for an invalid user name")
+	}
+}
@@ -0,0 +1,104 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// FetchSessionDocuments lists the Session Manager documents owned by the profile's account
+func FetchSessionDocuments(profile string) ([]string, error) {
+	client, err := NewSSMClient(profile)
+	if err != nil {
+		return nil, err
+	}
+	return listSessionDocuments(context.TODO(), client)
+}
+
+func listSessionDocuments(ctx context.Context, client ssm.ListDocumentsAPIClient) ([]string, error) {
+	pages := ssm.NewListDocumentsPaginator(client, &ssm.ListDocumentsInput{
+		Filters: []ssmtypes.DocumentKeyValuesFilter{
+			{Key: aws.String("DocumentType"), Values: []string{string(ssmtypes.DocumentTypeSession)}},
+			{Key: aws.String("Owner"), Values: []string{"Self"}},
+		},
+	})
+
+	var names []string
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list session documents failed: %w", err)
+		}
+		for _, doc := range page.DocumentIdentifiers {
+			names = append(names, aws.ToString(doc.Name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SessionDocument is what a Session Manager document lets a session choose
+type SessionDocument struct {
+	Name string
+	// Parameters are the parameter names the document takes
+	Parameters []string
+	// RunAsEnabled and RunAsUser are the document's Run As preferences
+	RunAsEnabled bool
+	RunAsUser    string
+}
+
+// Takes reports whether the document has the named parameter
+func (d SessionDocument) Takes(parameter string) bool {
+	return slices.Contains(d.Parameters, parameter)
+}
+
+// DocumentAPI is the subset of the SSM client used to read a document
+type DocumentAPI interface {
+	GetDocument(ctx context.Context, params *ssm.GetDocumentInput, optFns ...func(*ssm.Options)) (*ssm.GetDocumentOutput, error)
+}
+
+// FetchSessionDocument reads the parameters and Run As preferences of a Session Manager document
+func FetchSessionDocument(profile, name string) (SessionDocument, error) {
+	client, err := NewSSMClient(profile)
+	if err != nil {
+		return SessionDocument{}, err
+	}
+	return getSessionDocument(context.TODO(), client, name)
+}
+
+func getSessionDocument(ctx context.Context, client DocumentAPI, name string) (SessionDocument, error) {
+	out, err := client.GetDocument(ctx, &ssm.GetDocumentInput{
+		Name:           aws.String(name),
+		DocumentFormat: ssmtypes.DocumentFormatJson,
+	})
+	if err != nil {
+		return SessionDocument{}, fmt.Errorf("get document %s failed: %w", name, err)
+	}
+
+	var content struct {
+		Parameters map[string]json.RawMessage `json:"parameters"`
+		Inputs     struct {
+			RunAsEnabled     bool   `json:"runAsEnabled"`
+			RunAsDefaultUser string `json:"runAsDefaultUser"`
+		} `json:"inputs"`
+	}
+	if err := json.Unmarshal([]byte(aws.ToString(out.Content)), &content); err != nil {
+		return SessionDocument{}, fmt.Errorf("parse document %s failed: %w", name, err)
+	}
+	doc := SessionDocument{
+		Name:         name,
+		RunAsEnabled: content.Inputs.RunAsEnabled,
+		RunAsUser:    content.Inputs.RunAsDefaultUser,
+	}
+	for param := range content.Parameters {
+		doc.Parameters = append(doc.Parameters, param)
+	}
+	sort.Strings(doc.Parameters)
+	return doc, nil
+}
@@ -0,0 +1,38 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGetSessionDocument(t *testing.T) {
+	client := &fakeDocuments{content: map[string]string{
+		"SSM-SessionManagerRunShell":  `{"schemaVersion":"1.0","sessionType":"Standard_Stream","inputs":{"runAsEnabled":true,"runAsDefaultUser":"ops"}}`,
+		"AWS-StartInteractiveCommand": `{"schemaVersion":"1.0","sessionType":"InteractiveCommands","parameters":{"command":{"type":"String"}},"properties":{"linux":{"commands":"{{command}}"}}}`,
+		"Broken":                      `{`,
+	}}
+	tests := []struct {
+		name    string
+		want    SessionDocument
+		wantErr bool
+	}{
+		{name: "SSM-SessionManagerRunShell", want: SessionDocument{Name: "SSM-SessionManagerRunShell", RunAsEnabled: true, RunAsUser: "ops"}},
+		{name: "AWS-StartInteractiveCommand", want: SessionDocument{Name: "AWS-StartInteractiveCommand", Parameters: []string{"command"}}},
+		{name: "Broken", wantErr: true},
+		{name: "Missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := getSessionDocument(context.Background(), client, tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+	if doc, _ := getSessionDocument(context.Background(), client, "AWS-StartInteractiveCommand"); !doc.Takes("command") {
+		t.Error("AWS-StartInteractiveCommand should take a command")
+	}
+}
@@ -0,0 +1,183 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+// describeTasksBatch is the most task ARNs DescribeTasks accepts per call
+const describeTasksBatch = 100
+
+// Task is a running ECS container reachable through ECS Exec and its SSM managed agent
+type Task struct {
+	Cluster   string
+	Service   string
+	TaskID    string
+	Container string
+	RuntimeID string
+	VpcID     string
+}
+
+// Target returns the SSM target of the container's managed agent
+func (t Task) Target() string {
+	return fmt.Sprintf("ecs:%s_%s_%s", t.Cluster, t.TaskID, t.RuntimeID)
+}
+
+// Instance returns the task as a jump host for port-forwarding
+func (t Task) Instance() Instance {
+	return Instance{ID: t.Target(), Name: t.Label(), VpcID: t.VpcID}
+}
+
+// Label names the container as cluster/service/container
+func (t Task) Label() string {
+	group := t.Service
+	if group == "" {
+		group = t.TaskID
+	}
+	return fmt.Sprintf("%s/%s/%s", t.Cluster, group, t.Container)
+}
+
+// IsTaskTarget reports whether an instance ID is an ECS task target
+func IsTaskTarget(id string) bool {
+	return strings.HasPrefix(id, "ecs:")
+}
+
+// FetchTasks lists the running containers of exec-enabled ECS tasks for the profile.
+// Failed clusters do not abort the listing; their errors are joined into the returned error.
+func FetchTasks(profile string) ([]Task, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return nil, fmt.Errorf("load config failed: %w", err)
+	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load settings failed: %w", err)
+	}
+	d := NewDiscovery(cfg, prefs)
+	return fetchTasks(context.TODO(), d.ECS, d.EC2)
+}
+
+func fetchTasks(ctx context.Context, client ECSAPI, ec2Client EC2API) ([]Task, error) {
+	var (
+		result []Task
+		errs   callErrors
+	)
+
+	var clusters []string
+	clusterPages := ecs.NewListClustersPaginator(client, &ecs.ListClustersInput{})
+	for clusterPages.HasMorePages() {
+		page, err := clusterPages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("ListClusters: %w", err)
+		}
+		clusters = append(clusters, page.ClusterArns...)
+	}
+
+	subnets := map[int]string{}
+	var subnetIDs []string
+	for _, cluster := range clusters {
+		var arns []string
+		taskPages := ecs.NewListTasksPaginator(client, &ecs.ListTasksInput{
+			Cluster:       aws.String(cluster),
+			DesiredStatus: ecstypes.DesiredStatusRunning,
+		})
+		for taskPages.HasMorePages() {
+			page, err := taskPages.NextPage(ctx)
+			if err != nil {
+				errs.add("ListTasks", err)
+				break
+			}
+			arns = append(arns, page.TaskArns...)
+		}
+
+		for start := 0; start < len(arns); start += describeTasksBatch {
+			out, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+				Cluster: aws.String(cluster),
+				Tasks:   arns[start:min(start+describeTasksBatch, len(arns))],
+			})
+			if err != nil {
+				errs.add("DescribeTasks", err)
+				continue
+			}
+			for _, task := range out.Tasks {
+				if !task.EnableExecuteCommand {
+					continue
+				}
+				subnet := taskSubnet(task)
+				// standalone tasks are grouped by family instead of a service
+				service, isService := strings.CutPrefix(aws.ToString(task.Group), "service:")
+				if !isService {
+					service = ""
+				}
+				for _, c := range task.Containers {
+					if c.RuntimeId == nil || !execAgentRunning(c) {
+						continue
+					}
+					if subnet != "" {
+						subnets[len(result)] = subnet
+						subnetIDs = append(subnetIDs, subnet)
+					}
+					result = append(result, Task{
+						Cluster:   arnName(cluster),
+						Service:   service,
+						TaskID:    arnName(aws.ToString(task.TaskArn)),
+						Container: aws.ToString(c.Name),
+						RuntimeID: *c.RuntimeId,
+					})
+				}
+			}
+		}
+	}
+
+	// awsvpc tasks, including every Fargate task, report the subnet of their network interface
+	subnetToVpc, err := subnetVpcs(ctx, ec2Client, subnetIDs)
+	if err != nil {
+		errs.add("DescribeSubnets", err)
+	}
+	for i, subnet := range subnets {
+		result[i].VpcID = subnetToVpc[subnet]
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Label() < result[j].Label()
+	})
+	return result, errs.err()
+}
+
+// execAgentRunning reports whether a container's ECS Exec agent is up
+func execAgentRunning(c ecstypes.Container) bool {
+	for _, agent := range c.ManagedAgents {
+		if agent.Name == ecstypes.ManagedAgentNameExecuteCommandAgent {
+			return aws.ToString(agent.LastStatus) == "RUNNING"
+		}
+	}
+	return false
+}
+
+// taskSubnet returns the subnet of the task's elastic network interface, if it has one
+func taskSubnet(task ecstypes.Task) string {
+	for _, att := range task.Attachments {
+		if aws.ToString(att.Type) != "ElasticNetworkInterface" {
+			continue
+		}
+		for _, kv := range att.Details {
+			if aws.ToString(kv.Name) == "subnetId" {
+				return aws.ToString(kv.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// arnName returns the last path element of an ECS ARN, e.g. the cluster name or task ID
+func arnName(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
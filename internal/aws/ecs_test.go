@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+func TestFetchTasks(t *testing.T) {
+	const cluster = "arn:aws:ecs:eu-west-1:123456789012:cluster/prod"
+	agent := func(status string) []ecstypes.ManagedAgent {
+		return []ecstypes.ManagedAgent{{Name: ecstypes.ManagedAgentNameExecuteCommandAgent, LastStatus: aws.String(status)}}
+	}
+	eni := []ecstypes.Attachment{{
+		Type:    aws.String("ElasticNetworkInterface"),
+		Details: []ecstypes.KeyValuePair{{Name: aws.String("subnetId"), Value: aws.String("subnet-a")}},
+	}}
+
+	client := &fakeECS{
+		clusters: [][]string{{cluster}},
+		tasks: map[string][][]string{cluster: {
+			{"arn:aws:ecs:eu-west-1:123456789012:task/prod/aaa"},
+			{"arn:aws:ecs:eu-west-1:123456789012:task/prod/bbb", "arn:aws:ecs:eu-west-1:123456789012:task/prod/ccc"},
+		}},
+		details: map[string]ecstypes.Task{
+			"arn:aws:ecs:eu-west-1:123456789012:task/prod/aaa": {
+				TaskArn:              aws.String("arn:aws:ecs:eu-west-1:123456789012:task/prod/aaa"),
+				Group:                aws.String("service:api"),
+				EnableExecuteCommand: true,
+				Attachments:          eni,
+				Containers: []ecstypes.Container{
+					{Name: aws.String("app"), RuntimeId: aws.String("aaa-111"), ManagedAgents: agent("RUNNING")},
+					{Name: aws.String("log-router"), RuntimeId: aws.String("aaa-222"), ManagedAgents: agent("STOPPED")},
+				},
+			},
+			"arn:aws:ecs:eu-west-1:123456789012:task/prod/bbb": {
+				TaskArn:    aws.String("arn:aws:ecs:eu-west-1:123456789012:task/prod/bbb"),
+				Group:      aws.String("service:worker"),
+				Containers: []ecstypes.Container{{Name: aws.String("app"), RuntimeId: aws.String("bbb-111"), ManagedAgents: agent("RUNNING")}},
+			},
+			"arn:aws:ecs:eu-west-1:123456789012:task/prod/ccc": {
+				TaskArn:              aws.String("arn:aws:ecs:eu-west-1:123456789012:task/prod/ccc"),
+				Group:                aws.String("family:migrate"),
+				EnableExecuteCommand: true,
+				Containers:           []ecstypes.Container{{Name: aws.String("job"), RuntimeId: aws.String("ccc-111"), ManagedAgents: agent("RUNNING")}},
+			},
+		},
+	}
+	ec2Client := &fakeEC2{subnets: [][]ec2types.Subnet{{{SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-1")}}}}
+
+	tasks, err := fetchTasks(context.Background(), client, ec2Client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Label()+" "+task.Target()+" "+task.VpcID)
+	}
+	want := []string{
+		"prod/api/app ecs:prod_aaa_aaa-111 vpc-1",
+		"prod/ccc/job ecs:prod_ccc_ccc-111 ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tasks = %q, want %q", got, want)
+	}
+	if len(client.describe) != 1 || len(client.describe[0]) != 3 {
+		t.Errorf("DescribeTasks calls = %v, want one call with all 3 tasks", client.describe)
+	}
+}
+
+func TestTaskInstance(t *testing.T) {
+	inst := Task{Cluster: "prod", Service: "api", TaskID: "abc", Container: "app", RuntimeID: "abc-1", VpcID: "vpc-1"}.Instance()
+	if inst.ID != "ecs:prod_abc_abc-1" || inst.Name != "prod/api/app" || inst.VpcID != "vpc-1" || !IsTaskTarget(inst.ID) {
+		t.Errorf("instance = %+v", inst)
+	}
+}
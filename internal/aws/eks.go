@@ -0,0 +1,65 @@
+package aws
+
+import (
+	"sort"
+	"strings"
+)
+
+// eksFromTags reads the EKS cluster and node group of a worker node from the tags set by
+// managed node groups, eksctl, Karpenter or the kubernetes.io/cluster ownership tag
+func eksFromTags(tags map[string]string) (cluster, nodeGroup string) {
+	cluster = tags["eks:cluster-name"]
+	if cluster == "" {
+		cluster = tags["alpha.eksctl.io/cluster-name"]
+	}
+	if cluster == "" {
+		for key, value := range tags {
+			if name, ok := strings.CutPrefix(key, "kubernetes.io/cluster/"); ok && value == "owned" {
+				cluster = name
+				break
+			}
+		}
+	}
+	if cluster == "" {
+		return "", ""
+	}
+
+	for _, key := range []string{"eks:nodegroup-name", "alpha.eksctl.io/nodegroup-name", "karpenter.sh/nodepool"} {
+		if tags[key] != "" {
+			return cluster, tags[key]
+		}
+	}
+	return cluster, ""
+}
+
+// EKSClusters returns the sorted names of the EKS clusters the instances are nodes of
+func EKSClusters(instances []Instance) []string {
+	seen := map[string]bool{}
+	var clusters []string
+	for _, inst := range instances {
+		if inst.EKSCluster != "" && !seen[inst.EKSCluster] {
+			seen[inst.EKSCluster] = true
+			clusters = append(clusters, inst.EKSCluster)
+		}
+	}
+	sort.Strings(clusters)
+	return clusters
+}
+
+// RankNodes returns the nodes of an EKS cluster, the only jump hosts that route Service
+// ClusterIPs, ordered by node group and name
+func RankNodes(instances []Instance, cluster string) []Instance {
+	var members []Instance
+	for _, inst := range instances {
+		if inst.EKSCluster == cluster {
+			members = append(members, inst)
+		}
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		if members[i].NodeGroup != members[j].NodeGroup {
+			return members[i].NodeGroup < members[j].NodeGroup
+		}
+		return members[i].Name < members[j].Name
+	})
+	return members
+}
@@ -0,0 +1,50 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEKSFromTags(t *testing.T) {
+	tests := []struct {
+		name                   string
+		tags                   map[string]string
+		wantCluster, wantGroup string
+	}{
+		{name: "managed node group", tags: map[string]string{"eks:cluster-name": "prod", "eks:nodegroup-name": "general"}, wantCluster: "prod", wantGroup: "general"},
+		{name: "eksctl", tags: map[string]string{"alpha.eksctl.io/cluster-name": "dev", "alpha.eksctl.io/nodegroup-name": "ng-1"}, wantCluster: "dev", wantGroup: "ng-1"},
+		{name: "karpenter", tags: map[string]string{"kubernetes.io/cluster/prod": "owned", "karpenter.sh/nodepool": "spot"}, wantCluster: "prod", wantGroup: "spot"},
+		{name: "shared subnet tag is not membership", tags: map[string]string{"kubernetes.io/cluster/prod": "shared"}},
+		{name: "plain instance", tags: map[string]string{"Name": "bastion"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster, group := eksFromTags(tt.tags)
+			if cluster != tt.wantCluster || group != tt.wantGroup {
+				t.Errorf("eksFromTags = %q, %q; want %q, %q", cluster, group, tt.wantCluster, tt.wantGroup)
+			}
+		})
+	}
+}
+
+func TestRankNodes(t *testing.T) {
+	instances := []Instance{
+		{ID: "i-bastion", Name: "bastion", VpcID: "vpc-1"},
+		{ID: "i-other", Name: "other-node", VpcID: "vpc-2", EKSCluster: "staging"},
+		{ID: "i-spot", Name: "node-b", VpcID: "vpc-1", EKSCluster: "prod", NodeGroup: "spot"},
+		{ID: "i-general", Name: "node-a", VpcID: "vpc-1", EKSCluster: "prod", NodeGroup: "general"},
+		{ID: "ecs:prod_abc_abc-1", Name: "prod/api/app", VpcID: "vpc-1"},
+		{ID: "i-elsewhere", Name: "elsewhere", VpcID: "vpc-3"},
+	}
+	var got []string
+	for _, inst := range RankNodes(instances, "prod") {
+		got = append(got, inst.ID)
+	}
+	want := []string{"i-general", "i-spot"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RankNodes = %v, want %v", got, want)
+	}
+	if clusters := EKSClusters(instances); !reflect.DeepEqual(clusters, []string{"prod", "staging"}) {
+		t.Errorf("EKSClusters = %v", clusters)
+	}
+}
@@ -0,0 +1,241 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+)
+
+// elastiCacheProvider discovers node-based ElastiCache clusters and replication groups
+type elastiCacheProvider struct{}
+
+func (elastiCacheProvider) Name() string { return "elasticache" }
+
+func (elastiCacheProvider) Discover(ctx context.Context, d *Discovery) ([]Target, error) {
+	targets, err := fetchElastiCache(ctx, d.ElastiCache)
+	return withKind(targets, KindElastiCache), err
+}
+
+// serverlessCacheProvider discovers ElastiCache Serverless caches
+type serverlessCacheProvider struct{}
+
+func (serverlessCacheProvider) Name() string { return "elasticache-serverless" }
+
+func (serverlessCacheProvider) Discover(ctx context.Context, d *Discovery) ([]Target, error) {
+	targets, err := fetchServerlessCaches(ctx, d.ElastiCache, d.EC2)
+	return withKind(targets, KindElastiCache), err
+}
+
+// fetchElastiCache lists Memcached clusters, standalone Redis nodes and Redis and Valkey replication group endpoints
+func fetchElastiCache(ctx context.Context, client ElastiCacheAPI) ([]Target, error) {
+	var (
+		result []Target
+		errs   callErrors
+	)
+
+	vpcMap := map[string]string{}
+	subnetPages := elasticache.NewDescribeCacheSubnetGroupsPaginator(client, &elasticache.DescribeCacheSubnetGroupsInput{})
+	for subnetPages.HasMorePages() {
+		page, err := subnetPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeCacheSubnetGroups", err)
+			break
+		}
+		for _, sg := range page.CacheSubnetGroups {
+			if sg.CacheSubnetGroupName != nil && sg.VpcId != nil {
+				vpcMap[*sg.CacheSubnetGroupName] = *sg.VpcId
+			}
+		}
+	}
+
+	clusterVpcMap := map[string]string{}
+	clusterPages := elasticache.NewDescribeCacheClustersPaginator(client, &elasticache.DescribeCacheClustersInput{
+		ShowCacheNodeInfo: aws.Bool(true),
+	})
+	for clusterPages.HasMorePages() {
+		page, err := clusterPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeCacheClusters", err)
+			break
+		}
+		for _, cc := range page.CacheClusters {
+			id := aws.ToString(cc.CacheClusterId)
+			vpc := vpcMap[aws.ToString(cc.CacheSubnetGroupName)]
+			clusterVpcMap[id] = vpc
+
+			// replication group members are listed through their group below
+			if cc.ReplicationGroupId != nil {
+				continue
+			}
+			engine := strings.ToLower(aws.ToString(cc.Engine))
+
+			if cc.ConfigurationEndpoint != nil && cc.ConfigurationEndpoint.Address != nil {
+				result = append(result, Target{
+					Endpoint:   *cc.ConfigurationEndpoint.Address,
+					Port:       endpointPort(cc.ConfigurationEndpoint.Port, DetectCachePort(engine)),
+					VpcID:      vpc,
+					Role:       engine,
+					Identifier: id,
+					Cluster:    id,
+					Engine:     engine,
+				})
+			}
+
+			for _, node := range cc.CacheNodes {
+				if node.Endpoint == nil || node.Endpoint.Address == nil {
+					continue
+				}
+				role := fmt.Sprintf("%s-primary", engine)
+				if engine == "memcached" {
+					role = "memcached-node"
+				}
+				result = append(result, Target{
+					Endpoint:   *node.Endpoint.Address,
+					Port:       endpointPort(node.Endpoint.Port, DetectCachePort(engine)),
+					VpcID:      vpc,
+					Role:       role,
+					Identifier: id,
+					Cluster:    id,
+					Engine:     engine,
+				})
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	groupPages := elasticache.NewDescribeReplicationGroupsPaginator(client, &elasticache.DescribeReplicationGroupsInput{})
+	for groupPages.HasMorePages() {
+		page, err := groupPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeReplicationGroups", err)
+			break
+		}
+		for _, rg := range page.ReplicationGroups {
+			engine := strings.ToLower(aws.ToString(rg.Engine))
+			if engine != "redis" && engine != "valkey" {
+				continue
+			}
+			vpc := ""
+			if len(rg.MemberClusters) > 0 {
+				vpc = clusterVpcMap[rg.MemberClusters[0]]
+			}
+
+			if rg.ConfigurationEndpoint != nil && rg.ConfigurationEndpoint.Address != nil {
+				addr := *rg.ConfigurationEndpoint.Address
+				port := endpointPort(rg.ConfigurationEndpoint.Port, DetectCachePort(engine))
+				if !seen[addr] {
+					seen[addr] = true
+					result = append(result, Target{Endpoint: addr, Port: port, VpcID: vpc, Role: fmt.Sprintf("%s-primary", engine), Engine: engine})
+				}
+			}
+
+			for _, ng := range rg.NodeGroups {
+				for _, ep := range ng.NodeGroupMembers {
+					if ep.ReadEndpoint == nil || ep.ReadEndpoint.Address == nil {
+						continue
+					}
+					addr := *ep.ReadEndpoint.Address
+					port := endpointPort(ep.ReadEndpoint.Port, DetectCachePort(engine))
+					role := "replica"
+					if ep.CurrentRole != nil && *ep.CurrentRole == "primary" {
+						role = "primary"
+					}
+					if !seen[addr] {
+						seen[addr] = true
+						result = append(result, Target{
+							Endpoint: addr,
+							Port:     port,
+							VpcID:    vpc,
+							Role:     fmt.Sprintf("%s-%s", engine, role),
+							Engine:   engine,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return result, errs.err()
+}
+
+// fetchServerlessCaches lists ElastiCache Serverless endpoints and reader endpoints
+func fetchServerlessCaches(ctx context.Context, client ElastiCacheAPI, ec2Client EC2API) ([]Target, error) {
+	var (
+		result []Target
+		errs   callErrors
+	)
+
+	type serverless struct {
+		name, engine, subnet string
+		endpoint, reader     *string
+		port, readerPort     *int32
+	}
+	var caches []serverless
+	var subnetIDs []string
+
+	pages := elasticache.NewDescribeServerlessCachesPaginator(client, &elasticache.DescribeServerlessCachesInput{})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeServerlessCaches", err)
+			break
+		}
+		for _, sc := range page.ServerlessCaches {
+			c := serverless{
+				name:   aws.ToString(sc.ServerlessCacheName),
+				engine: strings.ToLower(aws.ToString(sc.Engine)),
+			}
+			if sc.Endpoint != nil {
+				c.endpoint, c.port = sc.Endpoint.Address, sc.Endpoint.Port
+			}
+			if sc.ReaderEndpoint != nil {
+				c.reader, c.readerPort = sc.ReaderEndpoint.Address, sc.ReaderEndpoint.Port
+			}
+			// serverless caches have no subnet group; all their subnets share one VPC
+			if len(sc.SubnetIds) > 0 {
+				c.subnet = sc.SubnetIds[0]
+				subnetIDs = append(subnetIDs, c.subnet)
+			}
+			caches = append(caches, c)
+		}
+	}
+	if len(caches) == 0 {
+		return nil, errs.err()
+	}
+
+	subnetToVpc, err := subnetVpcs(ctx, ec2Client, subnetIDs)
+	if err != nil {
+		errs.add("DescribeSubnets", err)
+	}
+
+	for _, c := range caches {
+		vpc := subnetToVpc[c.subnet]
+		if c.endpoint != nil {
+			result = append(result, Target{
+				Endpoint:   *c.endpoint,
+				Port:       endpointPort(c.port, DetectCachePort(c.engine)),
+				VpcID:      vpc,
+				Role:       "serverless",
+				Identifier: c.name,
+				Cluster:    c.name,
+				Engine:     c.engine,
+			})
+		}
+		if c.reader != nil {
+			result = append(result, Target{
+				Endpoint:   *c.reader,
+				Port:       endpointPort(c.readerPort, DetectCachePort(c.engine)),
+				VpcID:      vpc,
+				Role:       "serverless-reader",
+				Identifier: c.name,
+				Cluster:    c.name,
+				Engine:     c.engine,
+			})
+		}
+	}
+
+	return result, errs.err()
+}
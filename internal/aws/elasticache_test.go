@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+)
+
+func TestFetchElastiCache(t *testing.T) {
+	subnetGroups := [][]ectypes.CacheSubnetGroup{{
+		{CacheSubnetGroupName: aws.String("cache-private"), VpcId: aws.String("vpc-1")},
+	}}
+	endpoint := func(addr string, port int32) *ectypes.Endpoint {
+		return &ectypes.Endpoint{Address: aws.String(addr), Port: aws.Int32(port)}
+	}
+
+	tests := []struct {
+		name   string
+		client *fakeElastiCache
+		want   []string // "role endpoint:port vpc"
+	}{
+		{
+			name: "memcached config endpoint and nodes",
+			client: &fakeElastiCache{
+				subnetGroups: subnetGroups,
+				clusters: [][]ectypes.CacheCluster{{{
+					CacheClusterId:        aws.String("sessions"),
+					Engine:                aws.String("memcached"),
+					CacheSubnetGroupName:  aws.String("cache-private"),
+					ConfigurationEndpoint: endpoint("sessions.cfg.cache.amazonaws.com", 11211),
+					CacheNodes: []ectypes.CacheNode{
+						{Endpoint: endpoint("sessions.0001.cache.amazonaws.com", 11211)},
+						{Endpoint: nil},
+					},
+				}}},
+			},
+			want: []string{
+				"memcached sessions.cfg.cache.amazonaws.com:11211 vpc-1",
+				"memcached-node sessions.0001.cache.amazonaws.com:11211 vpc-1",
+			},
+		},
+		{
+			name: "standalone redis node keeps its real port",
+			client: &fakeElastiCache{
+				subnetGroups: subnetGroups,
+				clusters: [][]ectypes.CacheCluster{{{
+					CacheClusterId:       aws.String("queue"),
+					Engine:               aws.String("redis"),
+					CacheSubnetGroupName: aws.String("cache-private"),
+					CacheNodes:           []ectypes.CacheNode{{Endpoint: endpoint("queue.0001.cache.amazonaws.com", 6380)}},
+				}}},
+			},
+			want: []string{"redis-primary queue.0001.cache.amazonaws.com:6380 vpc-1"},
+		},
+		{
+			name: "replication group across pages maps VPC through member clusters",
+			client: &fakeElastiCache{
+				subnetGroups: subnetGroups,
+				clusters: [][]ectypes.CacheCluster{
+					{{CacheClusterId: aws.String("app-001"), ReplicationGroupId: aws.String("app"), CacheSubnetGroupName: aws.String("cache-private")}},
+					{{CacheClusterId: aws.String("app-002"), ReplicationGroupId: aws.String("app"), CacheSubnetGroupName: aws.String("cache-private")}},
+				},
+				replicationGroups: [][]ectypes.ReplicationGroup{
+					{{
+						ReplicationGroupId: aws.String("app"),
+						Engine:             aws.String("valkey"),
+						MemberClusters:     []string{"app-001", "app-002"},
+						NodeGroups: []ectypes.NodeGroup{{NodeGroupMembers: []ectypes.NodeGroupMember{
+							{CurrentRole: aws.String("primary"), ReadEndpoint: endpoint("app-001.cache.amazonaws.com", 6379)},
+							{CurrentRole: aws.String("replica"), ReadEndpoint: endpoint("app-002.cache.amazonaws.com", 6379)},
+							{CurrentRole: aws.String("replica")},
+						}}},
+					}},
+					{{ReplicationGroupId: aws.String("legacy"), Engine: aws.String("memcached")}},
+				},
+			},
+			want: []string{
+				"valkey-primary app-001.cache.amazonaws.com:6379 vpc-1",
+				"valkey-replica app-002.cache.amazonaws.com:6379 vpc-1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := fetchElastiCache(context.Background(), tt.client)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := describeTargets(targets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("targets =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestFetchServerlessCaches(t *testing.T) {
+	client := &fakeElastiCache{serverless: [][]ectypes.ServerlessCache{{{
+		ServerlessCacheName: aws.String("edge"),
+		Engine:              aws.String("redis"),
+		Endpoint:            &ectypes.Endpoint{Address: aws.String("edge.serverless.cache.amazonaws.com"), Port: aws.Int32(6379)},
+		ReaderEndpoint:      &ectypes.Endpoint{Address: aws.String("edge.serverless.cache.amazonaws.com"), Port: aws.Int32(6380)},
+		SubnetIds:           []string{"subnet-a", "subnet-b"},
+	}}}}
+	ec2Client := &fakeEC2{subnets: [][]ec2types.Subnet{{
+		{SubnetId: aws.String("subnet-a"), VpcId: aws.String("vpc-9")},
+	}}}
+
+	targets, err := fetchServerlessCaches(context.Background(), client, ec2Client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"serverless edge.serverless.cache.amazonaws.com:6379 vpc-9",
+		"serverless-reader edge.serverless.cache.amazonaws.com:6380 vpc-9",
+	}
+	if got := describeTargets(targets); !reflect.DeepEqual(got, want) {
+		t.Errorf("targets = %v, want %v", got, want)
+	}
+}
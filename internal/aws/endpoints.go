@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+// endpointServices lists the service IDs whose clients honour endpoint overrides.
+// Each maps to an AWS_ENDPOINT_URL_<SERVICE> variable and an "endpoints" settings key.
+var endpointServices = []string{"ec2", "ssm", "rds", "elasticache", "memorydb", "redshift", "opensearch", "kafka", "ec2-instance-connect", "ecs", "sts"}
+
+// EndpointURL returns the endpoint override for a service, or "" to use the AWS default.
+// Like the AWS CLI, service-specific variables win over AWS_ENDPOINT_URL,
+// which wins over the "endpoints" entries of the settings file.
+func EndpointURL(s *settings.Settings, service string) string {
+	env := "AWS_ENDPOINT_URL_" + strings.ToUpper(strings.ReplaceAll(service, "-", "_"))
+	if url := os.Getenv(env); url != "" {
+		return url
+	}
+	if url := os.Getenv("AWS_ENDPOINT_URL"); url != "" {
+		return url
+	}
+	if url := s.Endpoints[service]; url != "" {
+		return url
+	}
+	return s.Endpoints["default"]
+}
+
+// overrideEndpoint points a client at the configured endpoint, if any
+func overrideEndpoint(dst **string, s *settings.Settings, service string) {
+	if url := EndpointURL(s, service); url != "" {
+		*dst = aws.String(url)
+	}
+}
+
+// endpointsTag fingerprints active endpoint overrides so results from a local
+// emulator are cached apart from those of the real AWS account
+func endpointsTag(s *settings.Settings) string {
+	h := fnv.New32a()
+	active := false
+	for _, service := range endpointServices {
+		if url := EndpointURL(s, service); url != "" {
+			active = true
+			fmt.Fprintf(h, "%s=%s;", service, url)
+		}
+	}
+	if !active {
+		return ""
+	}
+	return fmt.Sprintf("_endpoints-%08x", h.Sum32())
+}
@@ -0,0 +1,80 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+func TestEndpointURL(t *testing.T) {
+	prefs := &settings.Settings{Endpoints: map[string]string{
+		"ssm":     "http://ssm.local:4566",
+		"default": "http://moto.local:5000",
+	}}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		service string
+		want    string
+	}{
+		{name: "settings entry for the service", service: "ssm", want: "http://ssm.local:4566"},
+		{name: "settings default", service: "rds", want: "http://moto.local:5000"},
+		{name: "global variable beats settings", env: map[string]string{"AWS_ENDPOINT_URL": "http://env:5000"}, service: "ssm", want: "http://env:5000"},
+		{
+			name:    "service variable beats global variable",
+			env:     map[string]string{"AWS_ENDPOINT_URL": "http://env:5000", "AWS_ENDPOINT_URL_ELASTICACHE": "http://cache:6000"},
+			service: "elasticache",
+			want:    "http://cache:6000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_ENDPOINT_URL", "")
+			t.Setenv("AWS_ENDPOINT_URL_SSM", "")
+			t.Setenv("AWS_ENDPOINT_URL_RDS", "")
+			t.Setenv("AWS_ENDPOINT_URL_ELASTICACHE", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if got := EndpointURL(prefs, tt.service); got != tt.want {
+				t.Errorf("EndpointURL(%q) = %q, want %q", tt.service, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointsTag(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	if tag := endpointsTag(&settings.Settings{}); tag != "" {
+		t.Errorf("endpointsTag without overrides = %q, want empty", tag)
+	}
+	local := endpointsTag(&settings.Settings{Endpoints: map[string]string{"default": "http://localhost:5000"}})
+	other := endpointsTag(&settings.Settings{Endpoints: map[string]string{"default": "http://localhost:4566"}})
+	if local == "" || local == other {
+		t.Errorf("endpointsTag = %q and %q, want distinct non-empty tags", local, other)
+	}
+}
+
+func TestProvidersTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		providers map[string]bool
+		want      string
+	}{
+		{"none listed", nil, ""},
+		{"all enabled", map[string]bool{"msk": true}, ""},
+		{"one disabled", map[string]bool{"msk": false}, providersTag(&settings.Settings{Providers: map[string]bool{"msk": false, "rds": true}})},
+	}
+	for _, tt := range tests {
+		if got := providersTag(&settings.Settings{Providers: tt.providers}); got != tt.want {
+			t.Errorf("%s: providersTag = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	msk := providersTag(&settings.Settings{Providers: map[string]bool{"msk": false}})
+	both := providersTag(&settings.Settings{Providers: map[string]bool{"msk": false, "opensearch": false}})
+	if msk == "" || msk == both {
+		t.Errorf("providersTag = %q and %q, want distinct non-empty tags", msk, both)
+	}
+}
@@ -0,0 +1,258 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	ectypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// pageOf returns the page addressed by a pagination token and the token of the following page.
+// Fakes encode tokens as page indexes so paginators walk every page in order.
+func pageOf[T any](pages [][]T, token *string) ([]T, *string) {
+	i := 0
+	if token != nil {
+		i, _ = strconv.Atoi(*token)
+	}
+	if i >= len(pages) {
+		return nil, nil
+	}
+	var next *string
+	if i+1 < len(pages) {
+		next = aws.String(strconv.Itoa(i + 1))
+	}
+	return pages[i], next
+}
+
+type fakeRDS struct {
+	clusters       [][]rdstypes.DBCluster
+	subnetGroups   [][]rdstypes.DBSubnetGroup
+	instances      [][]rdstypes.DBInstance
+	endpoints      [][]rdstypes.DBClusterEndpoint
+	proxies        [][]rdstypes.DBProxy
+	proxyEndpoints [][]rdstypes.DBProxyEndpoint
+	errs           map[string]error
+}
+
+func (f *fakeRDS) DescribeDBClusters(_ context.Context, in *rds.DescribeDBClustersInput, _ ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
+	if err := f.errs["DescribeDBClusters"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.clusters, in.Marker)
+	return &rds.DescribeDBClustersOutput{DBClusters: page, Marker: next}, nil
+}
+
+func (f *fakeRDS) DescribeDBSubnetGroups(_ context.Context, in *rds.DescribeDBSubnetGroupsInput, _ ...func(*rds.Options)) (*rds.DescribeDBSubnetGroupsOutput, error) {
+	if err := f.errs["DescribeDBSubnetGroups"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.subnetGroups, in.Marker)
+	return &rds.DescribeDBSubnetGroupsOutput{DBSubnetGroups: page, Marker: next}, nil
+}
+
+func (f *fakeRDS) DescribeDBInstances(_ context.Context, in *rds.DescribeDBInstancesInput, _ ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error) {
+	if err := f.errs["DescribeDBInstances"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.instances, in.Marker)
+	return &rds.DescribeDBInstancesOutput{DBInstances: page, Marker: next}, nil
+}
+
+func (f *fakeRDS) DescribeDBClusterEndpoints(_ context.Context, in *rds.DescribeDBClusterEndpointsInput, _ ...func(*rds.Options)) (*rds.DescribeDBClusterEndpointsOutput, error) {
+	if err := f.errs["DescribeDBClusterEndpoints"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.endpoints, in.Marker)
+	return &rds.DescribeDBClusterEndpointsOutput{DBClusterEndpoints: page, Marker: next}, nil
+}
+
+func (f *fakeRDS) DescribeDBProxies(_ context.Context, in *rds.DescribeDBProxiesInput, _ ...func(*rds.Options)) (*rds.DescribeDBProxiesOutput, error) {
+	if err := f.errs["DescribeDBProxies"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.proxies, in.Marker)
+	return &rds.DescribeDBProxiesOutput{DBProxies: page, Marker: next}, nil
+}
+
+func (f *fakeRDS) DescribeDBProxyEndpoints(_ context.Context, in *rds.DescribeDBProxyEndpointsInput, _ ...func(*rds.Options)) (*rds.DescribeDBProxyEndpointsOutput, error) {
+	if err := f.errs["DescribeDBProxyEndpoints"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.proxyEndpoints, in.Marker)
+	return &rds.DescribeDBProxyEndpointsOutput{DBProxyEndpoints: page, Marker: next}, nil
+}
+
+type fakeElastiCache struct {
+	subnetGroups      [][]ectypes.CacheSubnetGroup
+	clusters          [][]ectypes.CacheCluster
+	replicationGroups [][]ectypes.ReplicationGroup
+	serverless        [][]ectypes.ServerlessCache
+	errs              map[string]error
+}
+
+func (f *fakeElastiCache) DescribeCacheSubnetGroups(_ context.Context, in *elasticache.DescribeCacheSubnetGroupsInput, _ ...func(*elasticache.Options)) (*elasticache.DescribeCacheSubnetGroupsOutput, error) {
+	if err := f.errs["DescribeCacheSubnetGroups"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.subnetGroups, in.Marker)
+	return &elasticache.DescribeCacheSubnetGroupsOutput{CacheSubnetGroups: page, Marker: next}, nil
+}
+
+func (f *fakeElastiCache) DescribeCacheClusters(_ context.Context, in *elasticache.DescribeCacheClustersInput, _ ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	if err := f.errs["DescribeCacheClusters"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.clusters, in.Marker)
+	return &elasticache.DescribeCacheClustersOutput{CacheClusters: page, Marker: next}, nil
+}
+
+func (f *fakeElastiCache) DescribeReplicationGroups(_ context.Context, in *elasticache.DescribeReplicationGroupsInput, _ ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	if err := f.errs["DescribeReplicationGroups"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.replicationGroups, in.Marker)
+	return &elasticache.DescribeReplicationGroupsOutput{ReplicationGroups: page, Marker: next}, nil
+}
+
+func (f *fakeElastiCache) DescribeServerlessCaches(_ context.Context, in *elasticache.DescribeServerlessCachesInput, _ ...func(*elasticache.Options)) (*elasticache.DescribeServerlessCachesOutput, error) {
+	if err := f.errs["DescribeServerlessCaches"]; err != nil {
+		return nil, err
+	}
+	page, next := pageOf(f.serverless, in.NextToken)
+	return &elasticache.DescribeServerlessCachesOutput{ServerlessCaches: page, NextToken: next}, nil
+}
+
+type fakeEC2 struct {
+	subnets      [][]ec2types.Subnet
+	reservations [][]ec2types.Reservation
+	instanceIDs  [][]string // instance IDs requested per DescribeInstances call
+}
+
+func (f *fakeEC2) DescribeSubnets(_ context.Context, in *ec2.DescribeSubnetsInput, _ ...func(*ec2.Options)) (*ec2.DescribeSubnetsOutput, error) {
+	page, next := pageOf(f.subnets, in.NextToken)
+	return &ec2.DescribeSubnetsOutput{Subnets: page, NextToken: next}, nil
+}
+
+func (f *fakeEC2) DescribeInstances(_ context.Context, in *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	f.instanceIDs = append(f.instanceIDs, in.InstanceIds)
+	page, next := pageOf(f.reservations, in.NextToken)
+	// like EC2, only return the instances that were asked for
+	wanted := map[string]bool{}
+	for _, id := range in.InstanceIds {
+		wanted[id] = true
+	}
+	var out []ec2types.Reservation
+	for _, res := range page {
+		var insts []ec2types.Instance
+		for _, inst := range res.Instances {
+			if len(wanted) == 0 || wanted[aws.ToString(inst.InstanceId)] {
+				insts = append(insts, inst)
+			}
+		}
+		if len(insts) > 0 {
+			out = append(out, ec2types.Reservation{Instances: insts})
+		}
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: out, NextToken: next}, nil
+}
+
+type fakeSSM struct {
+	instances [][]ssmtypes.InstanceInformation
+}
+
+func (f *fakeSSM) DescribeInstanceInformation(_ context.Context, in *ssm.DescribeInstanceInformationInput, _ ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+	page, next := pageOf(f.instances, in.NextToken)
+	return &ssm.DescribeInstanceInformationOutput{InstanceInformationList: page, NextToken: next}, nil
+}
+
+type fakeCommands struct {
+	mu          sync.Mutex
+	sent        []*ssm.SendCommandInput
+	invocations []*ssm.GetCommandInvocationOutput // returned in order; nil means not visible yet
+	calls       int
+	byInstance  map[string]*ssm.GetCommandInvocationOutput
+	cancelled   []string
+	// sentAtFirstPoll is how many commands had been sent when the first invocation was polled
+	sentAtFirstPoll int
+}
+
+func (f *fakeCommands) CancelCommand(_ context.Context, in *ssm.CancelCommandInput, _ ...func(*ssm.Options)) (*ssm.CancelCommandOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cancelled = append(f.cancelled, aws.ToString(in.CommandId))
+	return &ssm.CancelCommandOutput{}, nil
+}
+
+func (f *fakeCommands) SendCommand(_ context.Context, in *ssm.SendCommandInput, _ ...func(*ssm.Options)) (*ssm.SendCommandOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, in)
+	return &ssm.SendCommandOutput{Command: &ssmtypes.Command{CommandId: aws.String(fmt.Sprintf("cmd-%d", len(f.sent)))}}, nil
+}
+
+func (f *fakeCommands) GetCommandInvocation(_ context.Context, in *ssm.GetCommandInvocationInput, _ ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.sentAtFirstPoll == 0 {
+		f.sentAtFirstPoll = len(f.sent)
+	}
+	if f.byInstance != nil {
+		return f.byInstance[aws.ToString(in.InstanceId)], nil
+	}
+	out := f.invocations[min(f.calls, len(f.invocations)-1)]
+	f.calls++
+	if out == nil {
+		return nil, &ssmtypes.InvocationDoesNotExist{}
+	}
+	return out, nil
+}
+
+type fakeDocuments struct {
+	content map[string]string
+}
+
+func (f *fakeDocuments) GetDocument(_ context.Context, in *ssm.GetDocumentInput, _ ...func(*ssm.Options)) (*ssm.GetDocumentOutput, error) {
+	content, ok := f.content[aws.ToString(in.Name)]
+	if !ok {
+		return nil, &ssmtypes.InvalidDocument{}
+	}
+	return &ssm.GetDocumentOutput{Name: in.Name, Content: aws.String(content)}, nil
+}
+
+type fakeECS struct {
+	clusters [][]string
+	tasks    map[string][][]string // task ARNs per cluster
+	details  map[string]ecstypes.Task
+	describe [][]string // task ARNs requested per DescribeTasks call
+}
+
+func (f *fakeECS) ListClusters(_ context.Context, in *ecs.ListClustersInput, _ ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	page, next := pageOf(f.clusters, in.NextToken)
+	return &ecs.ListClustersOutput{ClusterArns: page, NextToken: next}, nil
+}
+
+func (f *fakeECS) ListTasks(_ context.Context, in *ecs.ListTasksInput, _ ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	page, next := pageOf(f.tasks[aws.ToString(in.Cluster)], in.NextToken)
+	return &ecs.ListTasksOutput{TaskArns: page, NextToken: next}, nil
+}
+
+func (f *fakeECS) DescribeTasks(_ context.Context, in *ecs.DescribeTasksInput, _ ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	f.describe = append(f.describe, in.Tasks)
+	var tasks []ecstypes.Task
+	for _, arn := range in.Tasks {
+		tasks = append(tasks, f.details[arn])
+	}
+	return &ecs.DescribeTasksOutput{Tasks: tasks}, nil
+}
@@ -0,0 +1,29 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+// CallerARN returns the ARN of the IAM identity the profile's credentials belong to
+func CallerARN(ctx context.Context, profile string) (string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return "", fmt.Errorf("load config failed: %w", err)
+	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return "", fmt.Errorf("load settings failed: %w", err)
+	}
+	client := sts.NewFromConfig(cfg, func(o *sts.Options) { overrideEndpoint(&o.BaseEndpoint, prefs, "sts") })
+	out, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", fmt.Errorf("get caller identity failed: %w", err)
+	}
+	return aws.ToString(out.Arn), nil
+}
@@ -0,0 +1,48 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2instanceconnect"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+// InstanceConnectAPI is the subset of the EC2 Instance Connect client used to push keys
+type InstanceConnectAPI interface {
+	SendSSHPublicKey(ctx context.Context, params *ec2instanceconnect.SendSSHPublicKeyInput, optFns ...func(*ec2instanceconnect.Options)) (*ec2instanceconnect.SendSSHPublicKeyOutput, error)
+}
+
+// PushSSHPublicKey authorizes a public key for osUser on the instance through EC2 Instance Connect.
+// The key is only accepted for new connections during the next 60 seconds.
+func PushSSHPublicKey(profile, instanceID, osUser, publicKey string) error {
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return fmt.Errorf("load config failed: %w", err)
+	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return fmt.Errorf("load settings failed: %w", err)
+	}
+	client := ec2instanceconnect.NewFromConfig(cfg, func(o *ec2instanceconnect.Options) {
+		overrideEndpoint(&o.BaseEndpoint, prefs, "ec2-instance-connect")
+	})
+	return sendSSHPublicKey(context.TODO(), client, instanceID, osUser, publicKey)
+}
+
+func sendSSHPublicKey(ctx context.Context, client InstanceConnectAPI, instanceID, osUser, publicKey string) error {
+	out, err := client.SendSSHPublicKey(ctx, &ec2instanceconnect.SendSSHPublicKeyInput{
+		InstanceId:     aws.String(instanceID),
+		InstanceOSUser: aws.String(osUser),
+		SSHPublicKey:   aws.String(publicKey),
+	})
+	if err != nil {
+		return fmt.Errorf("send ssh public key failed: %w", err)
+	}
+	if !out.Success {
+		return fmt.Errorf("send ssh public key failed: request %s was not accepted", aws.ToString(out.RequestId))
+	}
+	return nil
+}
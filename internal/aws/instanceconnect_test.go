@@ -0,0 +1,34 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2instanceconnect"
+)
+
+type fakeInstanceConnect struct {
+	input   *ec2instanceconnect.SendSSHPublicKeyInput
+	success bool
+}
+
+func (f *fakeInstanceConnect) SendSSHPublicKey(_ context.Context, in *ec2instanceconnect.SendSSHPublicKeyInput, _ ...func(*ec2instanceconnect.Options)) (*ec2instanceconnect.SendSSHPublicKeyOutput, error) {
+	f.input = in
+	return &ec2instanceconnect.SendSSHPublicKeyOutput{Success: f.success, RequestId: aws.String("req-1")}, nil
+}
+
+func TestSendSSHPublicKey(t *testing.T) {
+	client := &fakeInstanceConnect{success: true}
+	if err := sendSSHPublicKey(context.Background(), client, "i-abc", "ec2-user", "ssh-ed25519 AAAA aws-ssm-connect"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if aws.ToString(client.input.InstanceOSUser) != "ec2-user" || aws.ToString(client.input.InstanceId) != "i-abc" {
+		t.Errorf("input = %+v", client.input)
+	}
+
+	client.success = false
+	if err := sendSSHPublicKey(context.Background(), client, "i-abc", "ec2-user", "ssh-ed25519 AAAA"); err == nil {
+		t.Error("expected an error when the key is not accepted")
+	}
+}
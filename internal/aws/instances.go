@@ -6,80 +6,109 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
 )
 
+// describeInstancesBatch bounds how many instance IDs are sent per DescribeInstances call
+const describeInstancesBatch = 100
+
 // Instance represents an EC2 instance
 type Instance struct {
 	ID    string
 	Name  string
 	VpcID string
+	Tags  map[string]string
+	// EKSCluster and NodeGroup are set for EKS worker nodes
+	EKSCluster string
+	NodeGroup  string
 }
 
 // FetchInstances returns all SSM-managed EC2 instances for the given profile
 func FetchInstances(profile string) ([]Instance, error) {
+	instances, err := fetchInstances(profile)
+	// Handle expired SSO token
+	if isExpiredToken(err) {
+		if err := EnsureSSOLogin(profile); err != nil {
+			return nil, fmt.Errorf("SSO login failed: %w", err)
+		}
+		instances, err = fetchInstances(profile)
+	}
+	return instances, err
+}
+
+// fetchInstances lists the profile's instances without ever starting an SSO login,
+// so it is safe to run while a prompt owns the terminal
+func fetchInstances(profile string) ([]Instance, error) {
 	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithSharedConfigProfile(profile))
 	if err != nil {
 		return nil, fmt.Errorf("load config failed: %w", err)
 	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load settings failed: %w", err)
+	}
+	return discoverInstances(context.TODO(), NewDiscovery(cfg, prefs))
+}
 
-	ssmClient := ssm.NewFromConfig(cfg)
-	paginator := ssm.NewDescribeInstanceInformationPaginator(ssmClient, &ssm.DescribeInstanceInformationInput{})
+// isExpiredToken reports whether err comes from an expired or revoked SSO token
+func isExpiredToken(err error) bool {
+	return err != nil && (strings.Contains(err.Error(), "token expired") ||
+		strings.Contains(err.Error(), "InvalidGrantException"))
+}
+
+// discoverInstances lists SSM-managed instances and resolves their names and VPCs through EC2
+func discoverInstances(ctx context.Context, d *Discovery) ([]Instance, error) {
+	paginator := ssm.NewDescribeInstanceInformationPaginator(d.SSM, &ssm.DescribeInstanceInformationInput{})
 
 	var ids []string
 	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.TODO())
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			// Handle expired SSO token
-			if strings.Contains(err.Error(), "token expired") ||
-				strings.Contains(err.Error(), "InvalidGrantException") {
-				if err := EnsureSSOLogin(profile); err != nil {
-					return nil, fmt.Errorf("SSO login failed: %w", err)
-				}
-				cfg, _ = config.LoadDefaultConfig(context.TODO(), config.WithSharedConfigProfile(profile))
-				ssmClient = ssm.NewFromConfig(cfg)
-				paginator = ssm.NewDescribeInstanceInformationPaginator(ssmClient, &ssm.DescribeInstanceInformationInput{})
-				continue
-			}
 			return nil, fmt.Errorf("describe ssm instances failed: %w", err)
 		}
 		for _, info := range page.InstanceInformationList {
-			ids = append(ids, *info.InstanceId)
+			// hybrid managed nodes (mi-...) have no EC2 metadata
+			if id := aws.ToString(info.InstanceId); strings.HasPrefix(id, "i-") {
+				ids = append(ids, id)
+			}
 		}
 	}
 	if len(ids) == 0 {
 		return nil, nil
 	}
 
-	ec2Client := ec2.NewFromConfig(cfg)
-	desc, err := ec2Client.DescribeInstances(context.TODO(), &ec2.DescribeInstancesInput{
-		InstanceIds: ids,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("describe ec2 instances failed: %w", err)
-	}
-
 	var result []Instance
-	for _, res := range desc.Reservations {
-		for _, inst := range res.Instances {
-			name := ""
-			for _, tag := range inst.Tags {
-				if *tag.Key == "Name" {
-					name = *tag.Value
-					break
-				}
+	for start := 0; start < len(ids); start += describeInstancesBatch {
+		end := min(start+describeInstancesBatch, len(ids))
+		pages := ec2.NewDescribeInstancesPaginator(d.EC2, &ec2.DescribeInstancesInput{
+			InstanceIds: ids[start:end],
+		})
+		for pages.HasMorePages() {
+			page, err := pages.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("describe ec2 instances failed: %w", err)
 			}
-			vpc := ""
-			if inst.VpcId != nil {
-				vpc = *inst.VpcId
+			for _, res := range page.Reservations {
+				for _, inst := range res.Instances {
+					tags := map[string]string{}
+					for _, tag := range inst.Tags {
+						tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+					}
+					cluster, nodeGroup := eksFromTags(tags)
+					result = append(result, Instance{
+						ID:         aws.ToString(inst.InstanceId),
+						Name:       tags["Name"],
+						VpcID:      aws.ToString(inst.VpcId),
+						Tags:       tags,
+						EKSCluster: cluster,
+						NodeGroup:  nodeGroup,
+					})
+				}
 			}
-			result = append(result, Instance{
-				ID:    *inst.InstanceId,
-				Name:  name,
-				VpcID: vpc,
-			})
 		}
 	}
 
@@ -88,3 +117,54 @@ func FetchInstances(profile string) ([]Instance, error) {
 	})
 	return result, nil
 }
+
+// MatchInstances returns the instances a query refers to: an instance ID, a Name tag
+// (case-insensitive) or a Key=Value tag selector
+func MatchInstances(instances []Instance, query string) []Instance {
+	var matches []Instance
+	key, value, isTag := strings.Cut(query, "=")
+	for _, inst := range instances {
+		switch {
+		case inst.ID == query:
+			return []Instance{inst}
+		case isTag:
+			if v, ok := inst.Tags[key]; ok && v == value {
+				matches = append(matches, inst)
+			}
+		case strings.EqualFold(inst.Name, query):
+			matches = append(matches, inst)
+		}
+	}
+	return matches
+}
+
+// FetchInstanceTags reads an instance's current tags straight from EC2, bypassing the cache
+func FetchInstanceTags(ctx context.Context, profile, instanceID string) (map[string]string, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return nil, fmt.Errorf("load config failed: %w", err)
+	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load settings failed: %w", err)
+	}
+	return instanceTags(ctx, NewDiscovery(cfg, prefs).EC2, instanceID)
+}
+
+// instanceTags returns the tags of a single instance
+func instanceTags(ctx context.Context, client EC2API, instanceID string) (map[string]string, error) {
+	out, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}})
+	if err != nil {
+		return nil, fmt.Errorf("describe ec2 instances failed: %w", err)
+	}
+	for _, res := range out.Reservations {
+		for _, inst := range res.Instances {
+			tags := map[string]string{}
+			for _, tag := range inst.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			return tags, nil
+		}
+	}
+	return nil, fmt.Errorf("instance %s not found", instanceID)
+}
@@ -0,0 +1,127 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestDiscoverInstances(t *testing.T) {
+	instance := func(id, name, vpc string) ec2types.Instance {
+		inst := ec2types.Instance{InstanceId: aws.String(id), VpcId: aws.String(vpc)}
+		if name != "" {
+			inst.Tags = []ec2types.Tag{{Key: aws.String("Name"), Value: aws.String(name)}}
+		}
+		return inst
+	}
+	ssmClient := &fakeSSM{instances: [][]ssmtypes.InstanceInformation{
+		{{InstanceId: aws.String("i-bbb")}, {InstanceId: aws.String("mi-0123")}},
+		{{InstanceId: aws.String("i-aaa")}},
+	}}
+	ec2Client := &fakeEC2{reservations: [][]ec2types.Reservation{
+		{{Instances: []ec2types.Instance{instance("i-bbb", "web", "vpc-1")}}},
+		{{Instances: []ec2types.Instance{instance("i-aaa", "bastion", "vpc-2")}}},
+	}}
+
+	got, err := discoverInstances(context.Background(), &Discovery{SSM: ssmClient, EC2: ec2Client})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Instance{
+		{ID: "i-aaa", Name: "bastion", VpcID: "vpc-2", Tags: map[string]string{"Name": "bastion"}},
+		{ID: "i-bbb", Name: "web", VpcID: "vpc-1", Tags: map[string]string{"Name": "web"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("instances = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(ec2Client.instanceIDs[0], []string{"i-bbb", "i-aaa"}) {
+		t.Errorf("DescribeInstances ids = %v, want hybrid nodes filtered out", ec2Client.instanceIDs[0])
+	}
+}
+
+func TestDiscoverInstancesBatchesEC2Calls(t *testing.T) {
+	var infos []ssmtypes.InstanceInformation
+	var insts []ec2types.Instance
+	for i := 0; i < 250; i++ {
+		id := fmt.Sprintf("i-%03d", i)
+		infos = append(infos, ssmtypes.InstanceInformation{InstanceId: aws.String(id)})
+		insts = append(insts, ec2types.Instance{InstanceId: aws.String(id)})
+	}
+	ec2Client := &fakeEC2{reservations: [][]ec2types.Reservation{{{Instances: insts}}}}
+
+	got, err := discoverInstances(context.Background(), &Discovery{
+		SSM: &fakeSSM{instances: [][]ssmtypes.InstanceInformation{infos}},
+		EC2: ec2Client,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 250 {
+		t.Errorf("got %d instances, want 250", len(got))
+	}
+	var sizes []int
+	for _, ids := range ec2Client.instanceIDs {
+		sizes = append(sizes, len(ids))
+	}
+	if !reflect.DeepEqual(sizes, []int{100, 100, 50}) {
+		t.Errorf("DescribeInstances batch sizes = %v, want [100 100 50]", sizes)
+	}
+}
+
+func TestDiscoverInstancesNoManagedNodes(t *testing.T) {
+	ec2Client := &fakeEC2{}
+	got, err := discoverInstances(context.Background(), &Discovery{SSM: &fakeSSM{}, EC2: ec2Client})
+	if err != nil || got != nil {
+		t.Fatalf("got %v, %v; want no instances", got, err)
+	}
+	if len(ec2Client.instanceIDs) != 0 {
+		t.Errorf("DescribeInstances called %d times, want 0", len(ec2Client.instanceIDs))
+	}
+}
+
+func TestMatchInstances(t *testing.T) {
+	instances := []Instance{
+		{ID: "i-001", Name: "api-1", Tags: map[string]string{"Name": "api-1", "Role": "api"}},
+		{ID: "i-002", Name: "api-2", Tags: map[string]string{"Name": "api-2", "Role": "api"}},
+		{ID: "i-003", Name: "Bastion", Tags: map[string]string{"Name": "Bastion", "Role": "bastion"}},
+	}
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"i-002", []string{"i-002"}},
+		{"bastion", []string{"i-003"}},
+		{"Role=api", []string{"i-001", "i-002"}},
+		{"Role=worker", nil},
+		{"api", nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, inst := range MatchInstances(instances, tt.query) {
+			got = append(got, inst.ID)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MatchInstances(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestInstanceTags(t *testing.T) {
+	client := &fakeEC2{reservations: [][]ec2types.Reservation{{{Instances: []ec2types.Instance{
+		{InstanceId: aws.String("i-aaa"), Tags: []ec2types.Tag{{Key: aws.String("Environment"), Value: aws.String("prod")}}},
+		{InstanceId: aws.String("i-bbb")},
+	}}}}}
+
+	tags, err := instanceTags(context.Background(), client, "i-aaa")
+	if err != nil || !reflect.DeepEqual(tags, map[string]string{"Environment": "prod"}) {
+		t.Errorf("tags = %v, %v; want Environment=prod", tags, err)
+	}
+	if tags, err := instanceTags(context.Background(), client, "i-ccc"); err == nil {
+		t.Errorf("tags of a missing instance = %v, want error", tags)
+	}
+}
@@ -0,0 +1,177 @@
+//go:build integration
+
+// Integration tests run discovery against a local moto server:
+//
+//	moto_server -p 5000 &
+//	MOTO_ENDPOINT=http://localhost:5000 go test -tags integration ./internal/aws/
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+)
+
+// motoDiscovery points every discovery client at the moto server through AWS_ENDPOINT_URL
+func motoDiscovery(t *testing.T) (*Discovery, aws.Config) {
+	t.Helper()
+	endpoint := os.Getenv("MOTO_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("MOTO_ENDPOINT is not set")
+	}
+	t.Setenv("AWS_ENDPOINT_URL", endpoint)
+	t.Setenv("AWS_ACCESS_KEY_ID", "testing")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "testing")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion("us-east-1"))
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	return NewDiscovery(cfg, &settings.Settings{}), cfg
+}
+
+// motoSubnets creates a VPC with two subnets in different availability zones
+func motoSubnets(t *testing.T, ctx context.Context, cfg aws.Config) (string, []string) {
+	t.Helper()
+	client := ec2.NewFromConfig(cfg)
+	vpc, err := client.CreateVpc(ctx, &ec2.CreateVpcInput{CidrBlock: aws.String("10.20.0.0/16")})
+	if err != nil {
+		t.Fatalf("CreateVpc: %v", err)
+	}
+	vpcID := aws.ToString(vpc.Vpc.VpcId)
+
+	var subnets []string
+	for i, az := range []string{"us-east-1a", "us-east-1b"} {
+		sn, err := client.CreateSubnet(ctx, &ec2.CreateSubnetInput{
+			VpcId:            aws.String(vpcID),
+			CidrBlock:        aws.String(fmt.Sprintf("10.20.%d.0/24", i)),
+			AvailabilityZone: aws.String(az),
+		})
+		if err != nil {
+			t.Fatalf("CreateSubnet: %v", err)
+		}
+		subnets = append(subnets, aws.ToString(sn.Subnet.SubnetId))
+	}
+	return vpcID, subnets
+}
+
+func TestIntegrationRDSDiscovery(t *testing.T) {
+	d, cfg := motoDiscovery(t)
+	ctx := context.Background()
+	vpcID, subnets := motoSubnets(t, ctx, cfg)
+
+	// moto keeps state between runs, so names are unique per run
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	client := rds.NewFromConfig(cfg)
+	group := "it-db-" + suffix
+	if _, err := client.CreateDBSubnetGroup(ctx, &rds.CreateDBSubnetGroupInput{
+		DBSubnetGroupName:        aws.String(group),
+		DBSubnetGroupDescription: aws.String("integration"),
+		SubnetIds:                subnets,
+	}); err != nil {
+		t.Fatalf("CreateDBSubnetGroup: %v", err)
+	}
+	instanceID := "it-orders-" + suffix
+	if _, err := client.CreateDBInstance(ctx, &rds.CreateDBInstanceInput{
+		DBInstanceIdentifier: aws.String(instanceID),
+		DBInstanceClass:      aws.String("db.t3.micro"),
+		Engine:               aws.String("postgres"),
+		AllocatedStorage:     aws.Int32(20),
+		MasterUsername:       aws.String("admin"),
+		MasterUserPassword:   aws.String("password123"),
+		DBSubnetGroupName:    aws.String(group),
+	}); err != nil {
+		t.Fatalf("CreateDBInstance: %v", err)
+	}
+	clusterID := "it-aurora-" + suffix
+	if _, err := client.CreateDBCluster(ctx, &rds.CreateDBClusterInput{
+		DBClusterIdentifier: aws.String(clusterID),
+		Engine:              aws.String("aurora-postgresql"),
+		MasterUsername:      aws.String("admin"),
+		MasterUserPassword:  aws.String("password123"),
+		DBSubnetGroupName:   aws.String(group),
+	}); err != nil {
+		t.Fatalf("CreateDBCluster: %v", err)
+	}
+
+	targets, err := rdsProvider{}.Discover(ctx, d)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+
+	roles := map[string]Target{}
+	for _, target := range targets {
+		if target.Identifier == instanceID || target.Cluster == clusterID {
+			roles[target.Role] = target
+		}
+	}
+	for _, role := range []string{"instance", "writer", "reader"} {
+		target, ok := roles[role]
+		if !ok {
+			t.Errorf("no %s target discovered; got %+v", role, targets)
+			continue
+		}
+		if target.Kind != KindRDS || target.Endpoint == "" || target.VpcID != vpcID {
+			t.Errorf("%s target = %+v, want an RDS endpoint in %s", role, target, vpcID)
+		}
+	}
+	if got := roles["instance"].Port; got != "5432" {
+		t.Errorf("instance port = %q, want 5432", got)
+	}
+}
+
+func TestIntegrationRedshiftDiscovery(t *testing.T) {
+	d, cfg := motoDiscovery(t)
+	ctx := context.Background()
+
+	clusterID := fmt.Sprintf("it-warehouse-%d", time.Now().UnixNano())
+	if _, err := redshift.NewFromConfig(cfg).CreateCluster(ctx, &redshift.CreateClusterInput{
+		ClusterIdentifier:  aws.String(clusterID),
+		NodeType:           aws.String("ra3.xlplus"),
+		MasterUsername:     aws.String("admin"),
+		MasterUserPassword: aws.String("Password123"),
+	}); err != nil {
+		t.Fatalf("CreateCluster: %v", err)
+	}
+
+	targets, err := redshiftProvider{}.Discover(ctx, d)
+	if err != nil {
+		t.Fatalf("discover: %v", err)
+	}
+	for _, target := range targets {
+		if target.Identifier == clusterID {
+			if target.Role != "leader" || target.Port != "5439" || target.Kind != KindRedshift {
+				t.Errorf("target = %+v, want a Redshift leader on 5439", target)
+			}
+			return
+		}
+	}
+	t.Errorf("cluster %s not discovered; got %+v", clusterID, targets)
+}
+
+func TestIntegrationSubnetVpcs(t *testing.T) {
+	d, cfg := motoDiscovery(t)
+	ctx := context.Background()
+	vpcID, subnets := motoSubnets(t, ctx, cfg)
+
+	got, err := subnetVpcs(ctx, d.EC2, subnets)
+	if err != nil {
+		t.Fatalf("subnetVpcs: %v", err)
+	}
+	for _, subnet := range subnets {
+		if got[subnet] != vpcID {
+			t.Errorf("subnet %s maps to %q, want %s", subnet, got[subnet], vpcID)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/memorydb"
+)
+
+// memoryDBProvider discovers MemoryDB clusters
+type memoryDBProvider struct{}
+
+func (memoryDBProvider) Name() string { return "memorydb" }
+
+func (memoryDBProvider) Discover(ctx context.Context, d *Discovery) ([]Target, error) {
+	targets, err := fetchMemoryDB(ctx, d.MemoryDB)
+	return withKind(targets, KindMemoryDB), err
+}
+
+// fetchMemoryDB lists MemoryDB cluster endpoints
+func fetchMemoryDB(ctx context.Context, client MemoryDBAPI) ([]Target, error) {
+	var (
+		result []Target
+		errs   callErrors
+	)
+
+	subnetToVpc := map[string]string{}
+	subnetPages := memorydb.NewDescribeSubnetGroupsPaginator(client, &memorydb.DescribeSubnetGroupsInput{})
+	for subnetPages.HasMorePages() {
+		page, err := subnetPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeSubnetGroups", err)
+			break
+		}
+		for _, sg := range page.SubnetGroups {
+			if sg.Name != nil && sg.VpcId != nil {
+				subnetToVpc[*sg.Name] = *sg.VpcId
+			}
+		}
+	}
+
+	clusterPages := memorydb.NewDescribeClustersPaginator(client, &memorydb.DescribeClustersInput{})
+	for clusterPages.HasMorePages() {
+		page, err := clusterPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeClusters", err)
+			break
+		}
+		for _, cluster := range page.Clusters {
+			if cluster.ClusterEndpoint == nil || cluster.ClusterEndpoint.Address == nil {
+				continue
+			}
+			name := aws.ToString(cluster.Name)
+			engine := strings.ToLower(aws.ToString(cluster.Engine))
+			if engine == "" {
+				engine = "redis"
+			}
+			result = append(result, Target{
+				Endpoint:   *cluster.ClusterEndpoint.Address,
+				Port:       endpointPort(&cluster.ClusterEndpoint.Port, "6379"),
+				VpcID:      subnetToVpc[aws.ToString(cluster.SubnetGroupName)],
+				Role:       "memorydb",
+				Identifier: name,
+				Cluster:    name,
+				Engine:     engine,
+			})
+		}
+	}
+
+	return result, errs.err()
+}
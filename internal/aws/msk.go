@@ -0,0 +1,105 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kafka"
+	"github.com/aws/aws-sdk-go-v2/service/kafka/types"
+)
+
+// mskProvider discovers MSK bootstrap brokers
+type mskProvider struct{}
+
+func (mskProvider) Name() string { return "msk" }
+
+func (mskProvider) Discover(ctx context.Context, d *Discovery) ([]Target, error) {
+	targets, err := fetchMSK(ctx, d.Kafka, d.EC2)
+	return withKind(targets, KindMSK), err
+}
+
+// fetchMSK lists the bootstrap brokers of provisioned and serverless MSK clusters
+func fetchMSK(ctx context.Context, client KafkaAPI, ec2Client EC2API) ([]Target, error) {
+	var (
+		result []Target
+		errs   callErrors
+	)
+
+	type cluster struct {
+		arn, name, subnet string
+	}
+	var clusters []cluster
+	var subnetIDs []string
+
+	pages := kafka.NewListClustersV2Paginator(client, &kafka.ListClustersV2Input{})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			errs.add("ListClustersV2", err)
+			break
+		}
+		for _, info := range page.ClusterInfoList {
+			if info.State != types.ClusterStateActive {
+				continue
+			}
+			c := cluster{arn: aws.ToString(info.ClusterArn), name: aws.ToString(info.ClusterName)}
+			var subnets []string
+			switch {
+			case info.Provisioned != nil && info.Provisioned.BrokerNodeGroupInfo != nil:
+				subnets = info.Provisioned.BrokerNodeGroupInfo.ClientSubnets
+			case info.Serverless != nil && len(info.Serverless.VpcConfigs) > 0:
+				subnets = info.Serverless.VpcConfigs[0].SubnetIds
+			}
+			if len(subnets) > 0 {
+				c.subnet = subnets[0]
+				subnetIDs = append(subnetIDs, c.subnet)
+			}
+			clusters = append(clusters, c)
+		}
+	}
+	if len(clusters) == 0 {
+		return nil, errs.err()
+	}
+
+	subnetToVpc, err := subnetVpcs(ctx, ec2Client, subnetIDs)
+	if err != nil {
+		errs.add("DescribeSubnets", err)
+	}
+
+	for _, c := range clusters {
+		out, err := client.GetBootstrapBrokers(ctx, &kafka.GetBootstrapBrokersInput{ClusterArn: aws.String(c.arn)})
+		if err != nil {
+			errs.add("GetBootstrapBrokers", err)
+			continue
+		}
+		brokers := []struct {
+			role    string
+			servers *string
+		}{
+			{"broker-plaintext", out.BootstrapBrokerString},
+			{"broker-tls", out.BootstrapBrokerStringTls},
+			{"broker-sasl-scram", out.BootstrapBrokerStringSaslScram},
+			{"broker-sasl-iam", out.BootstrapBrokerStringSaslIam},
+		}
+		for _, b := range brokers {
+			for _, server := range strings.Split(aws.ToString(b.servers), ",") {
+				host, port, ok := strings.Cut(strings.TrimSpace(server), ":")
+				if !ok || host == "" {
+					continue
+				}
+				result = append(result, Target{
+					Endpoint:   host,
+					Port:       port,
+					VpcID:      subnetToVpc[c.subnet],
+					Role:       b.role,
+					Identifier: c.name,
+					Cluster:    c.name,
+					Engine:     "kafka",
+				})
+			}
+		}
+	}
+
+	return result, errs.err()
+}
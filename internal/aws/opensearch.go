@@ -0,0 +1,74 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+)
+
+// openSearchProvider discovers VPC OpenSearch and Elasticsearch domains
+type openSearchProvider struct{}
+
+func (openSearchProvider) Name() string { return "opensearch" }
+
+func (openSearchProvider) Discover(ctx context.Context, d *Discovery) ([]Target, error) {
+	targets, err := fetchOpenSearch(ctx, d.OpenSearch)
+	return withKind(targets, KindOpenSearch), err
+}
+
+// describeDomainsBatch is the most domains DescribeDomains accepts per call
+const describeDomainsBatch = 5
+
+// fetchOpenSearch lists VPC endpoints of OpenSearch and Elasticsearch domains
+func fetchOpenSearch(ctx context.Context, client OpenSearchAPI) ([]Target, error) {
+	var (
+		result []Target
+		errs   callErrors
+	)
+
+	names, err := client.ListDomainNames(ctx, &opensearch.ListDomainNamesInput{})
+	if err != nil {
+		errs.add("ListDomainNames", err)
+		return nil, errs.err()
+	}
+
+	var domains []string
+	for _, d := range names.DomainNames {
+		if d.DomainName != nil {
+			domains = append(domains, *d.DomainName)
+		}
+	}
+
+	for start := 0; start < len(domains); start += describeDomainsBatch {
+		end := min(start+describeDomainsBatch, len(domains))
+		out, err := client.DescribeDomains(ctx, &opensearch.DescribeDomainsInput{DomainNames: domains[start:end]})
+		if err != nil {
+			errs.add("DescribeDomains", err)
+			continue
+		}
+		for _, domain := range out.DomainStatusList {
+			// public domains are reachable without a tunnel
+			if domain.VPCOptions == nil || domain.Endpoints["vpc"] == "" {
+				continue
+			}
+			name := aws.ToString(domain.DomainName)
+			engine := "opensearch"
+			if strings.HasPrefix(aws.ToString(domain.EngineVersion), "Elasticsearch") {
+				engine = "elasticsearch"
+			}
+			result = append(result, Target{
+				Endpoint:   domain.Endpoints["vpc"],
+				Port:       "443",
+				VpcID:      aws.ToString(domain.VPCOptions.VPCId),
+				Role:       "domain",
+				Identifier: name,
+				Cluster:    name,
+				Engine:     engine,
+			})
+		}
+	}
+
+	return result, errs.err()
+}
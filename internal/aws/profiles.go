@@ -11,22 +11,121 @@ import (
 	"gopkg.in/ini.v1"
 )
 
-// FetchProfiles parses ~/.aws/config and returns all available profile names
-func FetchProfiles() ([]string, error) {
-	path := filepath.Join(os.Getenv("HOME"), ".aws", "config")
-	cfg, err := ini.Load(path)
+// Profile represents a named profile from the shared AWS config and credentials files
+type Profile struct {
+	Name        string
+	AccountID   string
+	Region      string
+	SSOStartURL string
+	SSOSession  string
+}
+
+// configFilePath returns the shared config file path, honoring AWS_CONFIG_FILE
+func configFilePath() string {
+	if path := os.Getenv("AWS_CONFIG_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".aws", "config")
+}
+
+// credentialsFilePath returns the shared credentials file path, honoring AWS_SHARED_CREDENTIALS_FILE
+func credentialsFilePath() string {
+	if path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(os.Getenv("HOME"), ".aws", "credentials")
+}
+
+// FetchProfiles parses the shared config and credentials files and returns all available profiles
+func FetchProfiles() ([]Profile, error) {
+	profiles := map[string]*Profile{}
+	sessions := map[string]*ini.Section{}
+
+	configFile, err := loadIniIfExists(configFilePath())
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("parse config file failed: %w", err)
+	}
+	if configFile != nil {
+		for _, section := range configFile.Sections() {
+			name := section.Name()
+			switch {
+			case name == "default":
+				profiles[name] = profileFromSection(name, section)
+			case strings.HasPrefix(name, "profile "):
+				name = strings.TrimSpace(strings.TrimPrefix(name, "profile "))
+				profiles[name] = profileFromSection(name, section)
+			case strings.HasPrefix(name, "sso-session "):
+				sessions[strings.TrimSpace(strings.TrimPrefix(name, "sso-session "))] = section
+			}
+		}
+	}
+
+	credsFile, err := loadIniIfExists(credentialsFilePath())
+	if err != nil {
+		return nil, fmt.Errorf("parse credentials file failed: %w", err)
+	}
+	if credsFile != nil {
+		for _, section := range credsFile.Sections() {
+			name := section.Name()
+			if name == ini.DefaultSection {
+				continue
+			}
+			if _, ok := profiles[name]; !ok {
+				profiles[name] = profileFromSection(name, section)
+			}
+		}
 	}
-	var names []string
-	for _, section := range cfg.Sections() {
-		name := section.Name()
-		if strings.HasPrefix(name, "profile ") {
-			names = append(names, strings.TrimPrefix(name, "profile "))
+
+	// Resolve SSO start URLs referenced through sso-session blocks
+	for _, p := range profiles {
+		if p.SSOStartURL != "" || p.SSOSession == "" {
+			continue
 		}
+		if session, ok := sessions[p.SSOSession]; ok {
+			p.SSOStartURL = session.Key("sso_start_url").String()
+		}
+	}
+
+	result := make([]Profile, 0, len(profiles))
+	for _, p := range profiles {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// profileFromSection extracts the identifying fields of a profile section
+func profileFromSection(name string, section *ini.Section) *Profile {
+	accountID := section.Key("sso_account_id").String()
+	if accountID == "" {
+		accountID = AccountFromARN(section.Key("role_arn").String())
+	}
+	return &Profile{
+		Name:        name,
+		AccountID:   accountID,
+		Region:      section.Key("region").String(),
+		SSOStartURL: section.Key("sso_start_url").String(),
+		SSOSession:  section.Key("sso_session").String(),
+	}
+}
+
+// AccountFromARN returns the account ID field of an ARN, or "" if it cannot be parsed
+func AccountFromARN(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 6 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[4]
+}
+
+// loadIniIfExists loads an INI file, returning nil without error if it does not exist
+func loadIniIfExists(path string) (*ini.File, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
 	}
-	sort.Strings(names)
-	return names, nil
+	return ini.Load(path)
 }
 
 // EnsureSSOLogin runs `aws sso login` if current token is expired or invalid
@@ -0,0 +1,134 @@
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFetchProfiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		credentials string
+		want        []Profile
+	}{
+		{
+			name: "default and named profiles",
+			config: `[default]
+region = eu-west-1
+
+[profile dev]
+region = us-east-1
+role_arn = arn:aws:iam::111111111111:role/Dev
+`,
+			want: []Profile{
+				{Name: "default", Region: "eu-west-1"},
+				{Name: "dev", AccountID: "111111111111", Region: "us-east-1"},
+			},
+		},
+		{
+			name: "credentials-only profiles",
+			config: `[profile dev]
+region = us-east-1
+`,
+			credentials: `[dev]
+aws_access_key_id = AKIAEXAMPLE
+
+[ci]
+aws_access_key_id = AKIAEXAMPLE2
+`,
+			want: []Profile{
+				{Name: "ci"},
+				{Name: "dev", Region: "us-east-1"},
+			},
+		},
+		{
+			name: "sso-session resolution",
+			config: `[profile prod]
+sso_session = corp
+sso_account_id = 222222222222
+region = eu-central-1
+
+[profile legacy]
+sso_start_url = https://legacy.awsapps.com/start
+sso_account_id = 333333333333
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = eu-central-1
+`,
+			want: []Profile{
+				{Name: "legacy", AccountID: "333333333333", SSOStartURL: "https://legacy.awsapps.com/start"},
+				{Name: "prod", AccountID: "222222222222", Region: "eu-central-1", SSOStartURL: "https://corp.awsapps.com/start", SSOSession: "corp"},
+			},
+		},
+		{
+			name: "no files",
+			want: []Profile{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config")
+			credentialsPath := filepath.Join(dir, "credentials")
+			if tt.config != "" {
+				if err := os.WriteFile(configPath, []byte(tt.config), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.credentials != "" {
+				if err := os.WriteFile(credentialsPath, []byte(tt.credentials), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			t.Setenv("AWS_CONFIG_FILE", configPath)
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsPath)
+
+			got, err := FetchProfiles()
+			if err != nil {
+				t.Fatalf("FetchProfiles: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("profiles = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchProfilesHonoursHome(t *testing.T) {
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".aws"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".aws", "config"), []byte("[profile home]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("AWS_CONFIG_FILE", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "")
+
+	got, err := FetchProfiles()
+	if err != nil {
+		t.Fatalf("FetchProfiles: %v", err)
+	}
+	if want := []Profile{{Name: "home"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("profiles = %+v, want %+v", got, want)
+	}
+}
+
+func TestAccountFromARN(t *testing.T) {
+	tests := map[string]string{
+		"arn:aws:sts::123456789012:assumed-role/Admin/alice": "123456789012",
+		"arn:aws:iam::123456789012:user/bob":                 "123456789012",
+		"":                                                   "",
+		"not-an-arn":                                         "",
+	}
+	for arn, want := range tests {
+		if got := AccountFromARN(arn); got != want {
+			t.Errorf("AccountFromARN(%q) = %q, want %q", arn, got, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Provider discovers tunnel targets for one AWS service using the clients in d.
+// A non-nil error alongside targets reports a partial failure.
+type Provider interface {
+	Name() string
+	Discover(ctx context.Context, d *Discovery) ([]Target, error)
+}
+
+var providers = []Provider{
+	rdsProvider{},
+	rdsProxyProvider{},
+	elastiCacheProvider{},
+	serverlessCacheProvider{},
+	memoryDBProvider{},
+	redshiftProvider{},
+	openSearchProvider{},
+	mskProvider{},
+}
+
+// Register adds a discovery provider to the registry
+func Register(p Provider) {
+	providers = append(providers, p)
+}
+
+// Providers returns all registered discovery providers
+func Providers() []Provider {
+	return providers
+}
+
+// callErrors collects the failed API calls of a discovery that carries on past them
+type callErrors []error
+
+// add records that call failed with err
+func (e *callErrors) add(call string, err error) {
+	*e = append(*e, fmt.Errorf("%s: %w", call, err))
+}
+
+// err joins the recorded failures, or returns nil if there were none
+func (e callErrors) err() error {
+	return errors.Join(e...)
+}
+
+// withKind stamps the service kind on targets returned by a provider
+func withKind(targets []Target, kind string) []Target {
+	for i := range targets {
+		targets[i].Kind = kind
+	}
+	return targets
+}
+
+// warningsFrom splits a provider error into one warning per failed call
+func warningsFrom(service string, err error) []Warning {
+	if err == nil {
+		return nil
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []Warning{{Service: service, Err: err}}
+	}
+	var warnings []Warning
+	for _, e := range joined.Unwrap() {
+		warnings = append(warnings, Warning{Service: service, Err: e})
+	}
+	return warnings
+}
@@ -0,0 +1,90 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// rdsProxyProvider discovers RDS Proxy endpoints
+type rdsProxyProvider struct{}
+
+func (rdsProxyProvider) Name() string { return "rds-proxy" }
+
+func (rdsProxyProvider) Discover(ctx context.Context, d *Discovery) ([]Target, error) {
+	targets, err := fetchRDSProxies(ctx, d.RDS)
+	return withKind(targets, KindRDSProxy), err
+}
+
+// fetchRDSProxies lists RDS Proxy default endpoints and their additional read-only or custom endpoints
+func fetchRDSProxies(ctx context.Context, client RDSAPI) ([]Target, error) {
+	var (
+		result []Target
+		errs   callErrors
+	)
+
+	families := map[string]string{}
+	proxyPages := rds.NewDescribeDBProxiesPaginator(client, &rds.DescribeDBProxiesInput{})
+	for proxyPages.HasMorePages() {
+		page, err := proxyPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeDBProxies", err)
+			break
+		}
+		for _, proxy := range page.DBProxies {
+			name := aws.ToString(proxy.DBProxyName)
+			family := strings.ToLower(aws.ToString(proxy.EngineFamily))
+			families[name] = family
+			if proxy.Endpoint == nil {
+				continue
+			}
+			result = append(result, Target{
+				Endpoint:   *proxy.Endpoint,
+				Port:       DetectPort(family),
+				VpcID:      aws.ToString(proxy.VpcId),
+				Role:       "proxy",
+				Identifier: name,
+				Cluster:    name,
+				Engine:     family,
+			})
+		}
+	}
+
+	endpointPages := rds.NewDescribeDBProxyEndpointsPaginator(client, &rds.DescribeDBProxyEndpointsInput{})
+	for endpointPages.HasMorePages() {
+		page, err := endpointPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeDBProxyEndpoints", err)
+			break
+		}
+		for _, ep := range page.DBProxyEndpoints {
+			if aws.ToBool(ep.IsDefault) || ep.Endpoint == nil {
+				continue
+			}
+			proxy := aws.ToString(ep.DBProxyName)
+			family, ok := families[proxy]
+			if !ok {
+				continue
+			}
+			role := "proxy-custom"
+			if ep.TargetRole == types.DBProxyEndpointTargetRoleReadOnly {
+				role = "proxy-reader"
+			}
+			// additional endpoints may live in a different VPC than the proxy itself
+			result = append(result, Target{
+				Endpoint:   *ep.Endpoint,
+				Port:       DetectPort(family),
+				VpcID:      aws.ToString(ep.VpcId),
+				Role:       role,
+				Identifier: aws.ToString(ep.DBProxyEndpointName),
+				Cluster:    proxy,
+				Engine:     family,
+			})
+		}
+	}
+
+	return result, errs.err()
+}
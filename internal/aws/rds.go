@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// rdsProvider discovers RDS, Aurora, DocumentDB and Neptune endpoints
+type rdsProvider struct{}
+
+func (rdsProvider) Name() string { return "rds" }
+
+func (rdsProvider) Discover(ctx context.Context, d *Discovery) ([]Target, error) {
+	targets, err := fetchRDS(ctx, d.RDS)
+	return withKind(targets, KindRDS), err
+}
+
+// fetchRDS lists Aurora, DocumentDB, Neptune and Multi-AZ cluster, member and custom endpoints, standalone RDS instances and their read replicas
+func fetchRDS(ctx context.Context, client RDSAPI) ([]Target, error) {
+	var (
+		result []Target
+		errs   callErrors
+	)
+
+	subnetToVpc := map[string]string{}
+	subnetPages := rds.NewDescribeDBSubnetGroupsPaginator(client, &rds.DescribeDBSubnetGroupsInput{})
+	for subnetPages.HasMorePages() {
+		page, err := subnetPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeDBSubnetGroups", err)
+			break
+		}
+		for _, sg := range page.DBSubnetGroups {
+			if sg.DBSubnetGroupName != nil && sg.VpcId != nil {
+				subnetToVpc[*sg.DBSubnetGroupName] = *sg.VpcId
+			}
+		}
+	}
+
+	type clusterInfo struct {
+		vpc     string
+		port    string
+		engine  string
+		writers map[string]bool
+	}
+	clusters := map[string]clusterInfo{}
+
+	clusterPages := rds.NewDescribeDBClustersPaginator(client, &rds.DescribeDBClustersInput{})
+	for clusterPages.HasMorePages() {
+		page, err := clusterPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeDBClusters", err)
+			break
+		}
+		for _, cluster := range page.DBClusters {
+			engine := strings.ToLower(aws.ToString(cluster.Engine))
+			// DocumentDB and Neptune share the RDS API; Multi-AZ DB clusters are the only
+			// other clusters with a cluster-level instance class
+			clustered := strings.Contains(engine, "aurora") || engine == "docdb" || engine == "neptune"
+			if !clustered && cluster.DBClusterInstanceClass == nil {
+				continue
+			}
+			vpc := subnetToVpc[aws.ToString(cluster.DBSubnetGroup)]
+			port := DetectPort(engine)
+			if cluster.Port != nil {
+				port = fmt.Sprint(*cluster.Port)
+			}
+
+			id := aws.ToString(cluster.DBClusterIdentifier)
+			source := sourceName(aws.ToString(cluster.ReplicationSourceIdentifier))
+
+			info := clusterInfo{vpc: vpc, port: port, engine: engine, writers: map[string]bool{}}
+			for _, member := range cluster.DBClusterMembers {
+				info.writers[aws.ToString(member.DBInstanceIdentifier)] = aws.ToBool(member.IsClusterWriter)
+			}
+			clusters[id] = info
+
+			if cluster.Endpoint != nil {
+				result = append(result, Target{Endpoint: *cluster.Endpoint, Port: port, VpcID: vpc, Role: "writer", Identifier: id, Source: source, Cluster: id, Engine: engine})
+			}
+			if cluster.ReaderEndpoint != nil {
+				result = append(result, Target{Endpoint: *cluster.ReaderEndpoint, Port: port, VpcID: vpc, Role: "reader", Identifier: id, Source: source, Cluster: id, Engine: engine})
+			}
+		}
+	}
+
+	endpointPages := rds.NewDescribeDBClusterEndpointsPaginator(client, &rds.DescribeDBClusterEndpointsInput{})
+	for endpointPages.HasMorePages() {
+		page, err := endpointPages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeDBClusterEndpoints", err)
+			break
+		}
+		for _, ep := range page.DBClusterEndpoints {
+			if aws.ToString(ep.EndpointType) != "CUSTOM" || ep.Endpoint == nil {
+				continue
+			}
+			cluster := aws.ToString(ep.DBClusterIdentifier)
+			info, ok := clusters[cluster]
+			if !ok {
+				continue
+			}
+			result = append(result, Target{
+				Endpoint:   *ep.Endpoint,
+				Port:       info.port,
+				VpcID:      info.vpc,
+				Role:       "custom",
+				Identifier: aws.ToString(ep.DBClusterEndpointIdentifier),
+				Cluster:    cluster,
+				Engine:     info.engine,
+			})
+		}
+	}
+
+	instancePages := rds.NewDescribeDBInstancesPaginator(client, &rds.DescribeDBInstancesInput{})
+	for instancePages.HasMorePages() {
+		page, err := instancePages.NextPage(ctx)
+		if err != nil {
+			errs.add("DescribeDBInstances", err)
+			break
+		}
+		for _, inst := range page.DBInstances {
+			// instances that are creating or stopped have no endpoint yet
+			if inst.Endpoint == nil || inst.Endpoint.Address == nil {
+				continue
+			}
+			port := DetectPort(aws.ToString(inst.Engine))
+			if inst.Endpoint.Port != nil {
+				port = fmt.Sprint(*inst.Endpoint.Port)
+			}
+			vpc := ""
+			if inst.DBSubnetGroup != nil && inst.DBSubnetGroup.VpcId != nil {
+				vpc = *inst.DBSubnetGroup.VpcId
+			}
+			id := aws.ToString(inst.DBInstanceIdentifier)
+			db := Target{
+				Endpoint:   *inst.Endpoint.Address,
+				Port:       port,
+				VpcID:      vpc,
+				Role:       "instance",
+				Identifier: id,
+				Engine:     strings.ToLower(aws.ToString(inst.Engine)),
+			}
+
+			// cluster members keep their own endpoint, labeled with their current role
+			if inst.DBClusterIdentifier != nil {
+				info, ok := clusters[*inst.DBClusterIdentifier]
+				if !ok {
+					continue
+				}
+				db.Cluster = *inst.DBClusterIdentifier
+				db.Class = aws.ToString(inst.DBInstanceClass)
+				db.Role = "member-reader"
+				if info.writers[id] {
+					db.Role = "member-writer"
+				}
+				result = append(result, db)
+				continue
+			}
+
+			switch {
+			case inst.ReadReplicaSourceDBInstanceIdentifier != nil:
+				db.Role = "replica"
+				db.Source = sourceName(*inst.ReadReplicaSourceDBInstanceIdentifier)
+			case inst.ReadReplicaSourceDBClusterIdentifier != nil:
+				db.Role = "replica"
+				db.Source = sourceName(*inst.ReadReplicaSourceDBClusterIdentifier)
+			}
+			result = append(result, db)
+		}
+	}
+
+	return result, errs.err()
+}
+
+// sourceName shortens a cross-region source ARN to its identifier
+func sourceName(source string) string {
+	if strings.HasPrefix(source, "arn:") {
+		return source[strings.LastIndex(source, ":")+1:]
+	}
+	return source
+}
@@ -0,0 +1,164 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+func TestFetchRDS(t *testing.T) {
+	subnetGroups := [][]rdstypes.DBSubnetGroup{{
+		{DBSubnetGroupName: aws.String("db-private"), VpcId: aws.String("vpc-1")},
+	}}
+	aurora := rdstypes.DBCluster{
+		DBClusterIdentifier: aws.String("orders"),
+		Engine:              aws.String("aurora-postgresql"),
+		DBSubnetGroup:       aws.String("db-private"),
+		Port:                aws.Int32(5432),
+		Endpoint:            aws.String("orders.cluster-x.rds.amazonaws.com"),
+		ReaderEndpoint:      aws.String("orders.cluster-ro-x.rds.amazonaws.com"),
+		DBClusterMembers: []rdstypes.DBClusterMember{
+			{DBInstanceIdentifier: aws.String("orders-1"), IsClusterWriter: aws.Bool(true)},
+			{DBInstanceIdentifier: aws.String("orders-2"), IsClusterWriter: aws.Bool(false)},
+		},
+	}
+	member := func(id, class string) rdstypes.DBInstance {
+		return rdstypes.DBInstance{
+			DBInstanceIdentifier: aws.String(id),
+			DBClusterIdentifier:  aws.String("orders"),
+			DBInstanceClass:      aws.String(class),
+			Engine:               aws.String("aurora-postgresql"),
+			Endpoint:             &rdstypes.Endpoint{Address: aws.String(id + ".x.rds.amazonaws.com"), Port: aws.Int32(5432)},
+			DBSubnetGroup:        &rdstypes.DBSubnetGroup{VpcId: aws.String("vpc-1")},
+		}
+	}
+	standalone := func(id string) rdstypes.DBInstance {
+		return rdstypes.DBInstance{
+			DBInstanceIdentifier: aws.String(id),
+			Engine:               aws.String("mysql"),
+			Endpoint:             &rdstypes.Endpoint{Address: aws.String(id + ".x.rds.amazonaws.com"), Port: aws.Int32(3306)},
+			DBSubnetGroup:        &rdstypes.DBSubnetGroup{VpcId: aws.String("vpc-2")},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		client  *fakeRDS
+		want    []string // "role endpoint:port vpc"
+		wantErr string
+	}{
+		{
+			name: "aurora cluster with members and custom endpoint",
+			client: &fakeRDS{
+				subnetGroups: subnetGroups,
+				clusters:     [][]rdstypes.DBCluster{{aurora}},
+				endpoints: [][]rdstypes.DBClusterEndpoint{{
+					{DBClusterIdentifier: aws.String("orders"), EndpointType: aws.String("WRITER"), Endpoint: aws.String("orders.cluster-x.rds.amazonaws.com")},
+					{DBClusterIdentifier: aws.String("orders"), EndpointType: aws.String("CUSTOM"), DBClusterEndpointIdentifier: aws.String("reporting"), Endpoint: aws.String("reporting.cluster-custom-x.rds.amazonaws.com")},
+				}},
+				instances: [][]rdstypes.DBInstance{{member("orders-1", "db.r6g.large"), member("orders-2", "db.r6g.xlarge")}},
+			},
+			want: []string{
+				"writer orders.cluster-x.rds.amazonaws.com:5432 vpc-1",
+				"reader orders.cluster-ro-x.rds.amazonaws.com:5432 vpc-1",
+				"custom reporting.cluster-custom-x.rds.amazonaws.com:5432 vpc-1",
+				"member-writer orders-1.x.rds.amazonaws.com:5432 vpc-1",
+				"member-reader orders-2.x.rds.amazonaws.com:5432 vpc-1",
+			},
+		},
+		{
+			name: "instances across pages with replica and nil endpoint",
+			client: &fakeRDS{
+				instances: [][]rdstypes.DBInstance{
+					{standalone("billing")},
+					{
+						func() rdstypes.DBInstance {
+							r := standalone("billing-replica")
+							r.ReadReplicaSourceDBInstanceIdentifier = aws.String("arn:aws:rds:eu-west-1:123456789012:db:billing")
+							return r
+						}(),
+						{DBInstanceIdentifier: aws.String("creating"), Engine: aws.String("postgres")},
+					},
+				},
+			},
+			want: []string{
+				"instance billing.x.rds.amazonaws.com:3306 vpc-2",
+				"replica billing-replica.x.rds.amazonaws.com:3306 vpc-2",
+			},
+		},
+		{
+			name: "single-instance clusters of other engines are skipped",
+			client: &fakeRDS{
+				subnetGroups: subnetGroups,
+				clusters: [][]rdstypes.DBCluster{{
+					{DBClusterIdentifier: aws.String("plain"), Engine: aws.String("mysql"), Endpoint: aws.String("plain.cluster-x.rds.amazonaws.com")},
+					{DBClusterIdentifier: aws.String("multi-az"), Engine: aws.String("postgres"), DBClusterInstanceClass: aws.String("db.m6gd.large"), DBSubnetGroup: aws.String("db-private"), Endpoint: aws.String("multi-az.cluster-x.rds.amazonaws.com")},
+					{DBClusterIdentifier: aws.String("docs"), Engine: aws.String("docdb"), DBSubnetGroup: aws.String("db-private"), Endpoint: aws.String("docs.cluster-x.docdb.amazonaws.com")},
+				}},
+			},
+			want: []string{
+				"writer multi-az.cluster-x.rds.amazonaws.com:5432 vpc-1",
+				"writer docs.cluster-x.docdb.amazonaws.com:27017 vpc-1",
+			},
+		},
+		{
+			name: "failed call keeps the rest",
+			client: &fakeRDS{
+				subnetGroups: subnetGroups,
+				clusters:     [][]rdstypes.DBCluster{{aurora}},
+				errs:         map[string]error{"DescribeDBInstances": errors.New("AccessDenied")},
+			},
+			want: []string{
+				"writer orders.cluster-x.rds.amazonaws.com:5432 vpc-1",
+				"reader orders.cluster-ro-x.rds.amazonaws.com:5432 vpc-1",
+			},
+			wantErr: "DescribeDBInstances: AccessDenied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := fetchRDS(context.Background(), tt.client)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if got := describeTargets(targets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("targets =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestFetchRDSReplicaSource(t *testing.T) {
+	client := &fakeRDS{instances: [][]rdstypes.DBInstance{{{
+		DBInstanceIdentifier:                  aws.String("reports"),
+		Engine:                                aws.String("postgres"),
+		Endpoint:                              &rdstypes.Endpoint{Address: aws.String("reports.x.rds.amazonaws.com"), Port: aws.Int32(5432)},
+		ReadReplicaSourceDBInstanceIdentifier: aws.String("arn:aws:rds:us-east-1:123456789012:db:orders"),
+	}}}}
+
+	targets, err := fetchRDS(context.Background(), client)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 1 || targets[0].Source != "orders" {
+		t.Fatalf("targets = %+v, want one replica of orders", targets)
+	}
+}
+
+// describeTargets renders the fields discovery tests assert on
+func describeTargets(targets []Target) []string {
+	var out []string
+	for _, t := range targets {
+		out = append(out, t.Role+" "+t.Endpoint+":"+t.Port+" "+t.VpcID)
+	}
+	return out
+}
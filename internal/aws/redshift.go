@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+)
+
+// redshiftProvider discovers provisioned Redshift clusters
+type redshiftProvider struct{}
+
+func (redshiftProvider) Name() string { return "redshift" }
+
+func (redshiftProvider) Discover(ctx context.Context, d *Discovery) ([]Target, error) {
+	targets, err := fetchRedshift(ctx, d.Redshift)
+	return withKind(targets, KindRedshift), err
+}
+
+// fetchRedshift lists provisioned Redshift cluster leader endpoints
+func fetchRedshift(ctx context.Context, client RedshiftAPI) ([]Target, error) {
+	var (
+		result []Target
+		errs   []error
+	)
+
+	pages := redshift.NewDescribeClustersPaginator(client, &redshift.DescribeClustersInput{})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("DescribeClusters: %w", err))
+			break
+		}
+		for _, cluster := range page.Clusters {
+			// clusters that are creating or paused have no endpoint
+			if cluster.Endpoint == nil || cluster.Endpoint.Address == nil {
+				continue
+			}
+			id := aws.ToString(cluster.ClusterIdentifier)
+			result = append(result, Target{
+				Endpoint:   *cluster.Endpoint.Address,
+				Port:       endpointPort(cluster.Endpoint.Port, "5439"),
+				VpcID:      aws.ToString(cluster.VpcId),
+				Role:       "leader",
+				Identifier: id,
+				Cluster:    id,
+				Engine:     "redshift",
+			})
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
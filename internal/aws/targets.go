@@ -0,0 +1,265 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
+	"golang.org/x/sync/errgroup"
+)
+
+// Service kinds a Target can belong to, in the order they are listed in the picker
+const (
+	KindRDS         = "rds"
+	KindRDSProxy    = "rds-proxy"
+	KindElastiCache = "elasticache"
+	KindMemoryDB    = "memorydb"
+	KindRedshift    = "redshift"
+	KindOpenSearch  = "opensearch"
+	KindMSK         = "msk"
+)
+
+var kindOrder = []string{KindRDS, KindRDSProxy, KindElastiCache, KindMemoryDB, KindRedshift, KindOpenSearch, KindMSK}
+
+// Target represents a discovered endpoint that can be reached through a tunnel
+type Target struct {
+	Kind       string // service the endpoint belongs to, one of the Kind constants
+	Endpoint   string
+	Port       string
+	VpcID      string
+	Role       string
+	Identifier string
+	Source     string // identifier of the replication source, for replicas
+	Cluster    string // owning cluster, proxy or domain, used to group related endpoints
+	Class      string // instance class, for cluster members
+	Engine     string // engine, or engine family for proxies
+}
+
+// IsReaderRole reports whether a target role only serves reads
+func IsReaderRole(role string) bool {
+	switch role {
+	case "reader", "replica", "member-reader", "proxy-reader", "serverless-reader":
+		return true
+	}
+	return strings.HasSuffix(role, "-replica")
+}
+
+// Warning records a discovery call that failed while others succeeded
+type Warning struct {
+	Service string
+	Err     error
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%s: %v", w.Service, w.Err)
+}
+
+// FetchTargets runs every enabled discovery provider for the given AWS profile.
+// Failed service calls do not abort discovery; they are returned as warnings
+// alongside whatever could still be listed.
+func FetchTargets(profile string) ([]Target, []Warning, error) {
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return nil, nil, err
+	}
+	prefs, err := settings.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("load settings failed: %w", err)
+	}
+
+	var (
+		mu       sync.Mutex
+		targets  []Target
+		warnings []Warning
+	)
+	discovery := NewDiscovery(cfg, prefs)
+	eg, ctx := errgroup.WithContext(context.Background())
+
+	for _, p := range Providers() {
+		if !prefs.ProviderEnabled(p.Name()) {
+			continue
+		}
+		eg.Go(func() error {
+			result, err := p.Discover(ctx, discovery)
+			mu.Lock()
+			targets = append(targets, result...)
+			warnings = append(warnings, warningsFrom(p.Name(), err)...)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	sortTargets(targets)
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Service < warnings[j].Service
+	})
+
+	return targets, warnings, nil
+}
+
+// subnetVpcs resolves subnet IDs to their VPC IDs for services that only report subnets
+func subnetVpcs(ctx context.Context, client EC2API, subnetIDs []string) (map[string]string, error) {
+	result := map[string]string{}
+	if len(subnetIDs) == 0 {
+		return result, nil
+	}
+	pages := ec2.NewDescribeSubnetsPaginator(client, &ec2.DescribeSubnetsInput{SubnetIds: subnetIDs})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return result, err
+		}
+		for _, sn := range page.Subnets {
+			result[aws.ToString(sn.SubnetId)] = aws.ToString(sn.VpcId)
+		}
+	}
+	return result, nil
+}
+
+// sortTargets orders targets by service, then by cluster, proxy or domain so that
+// member and custom endpoints follow their cluster's writer and reader endpoints
+func sortTargets(targets []Target) {
+	kinds := map[string]int{}
+	for i, kind := range kindOrder {
+		kinds[kind] = i
+	}
+	group := func(t Target) string {
+		if t.Cluster != "" {
+			return t.Cluster
+		}
+		if t.Identifier != "" {
+			return t.Identifier
+		}
+		return t.Endpoint
+	}
+	rank := map[string]int{
+		"writer": 0, "reader": 1, "custom": 2, "member-writer": 3, "member-reader": 4,
+		"proxy": 0, "proxy-reader": 1, "proxy-custom": 2,
+		"memcached": 0, "memcached-node": 1, "serverless": 0, "serverless-reader": 1,
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		if ki, kj := kinds[targets[i].Kind], kinds[targets[j].Kind]; ki != kj {
+			return ki < kj
+		}
+		gi, gj := group(targets[i]), group(targets[j])
+		if gi != gj {
+			return gi < gj
+		}
+		ri, ok := rank[targets[i].Role]
+		if !ok {
+			ri = len(rank)
+		}
+		rj, ok := rank[targets[j].Role]
+		if !ok {
+			rj = len(rank)
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return targets[i].Endpoint < targets[j].Endpoint
+	})
+}
+
+// endpointPort formats an endpoint's port, falling back when the API omits it
+func endpointPort(port *int32, fallback string) string {
+	if port == nil || *port == 0 {
+		return fallback
+	}
+	return fmt.Sprint(*port)
+}
+
+// DetectCachePort returns the default port of an ElastiCache engine
+func DetectCachePort(engine string) string {
+	if strings.ToLower(engine) == "memcached" {
+		return "11211"
+	}
+	return "6379"
+}
+
+func DetectPort(engine string) string {
+	engine = strings.ToLower(engine)
+	switch {
+	case strings.Contains(engine, "docdb"):
+		return "27017"
+	case strings.Contains(engine, "neptune"):
+		return "8182"
+	case strings.Contains(engine, "redshift"):
+		return "5439"
+	case strings.Contains(engine, "mysql"):
+		return "3306"
+	case strings.Contains(engine, "postgres"):
+		return "5432"
+	case strings.Contains(engine, "sqlserver"):
+		return "1433"
+	case strings.Contains(engine, "oracle"):
+		return "1521"
+	case strings.Contains(engine, "mongo"):
+		return "27017"
+	default:
+		return "3306"
+	}
+}
+
+// DetectEngineByPort returns the engine name based on default port number
+func DetectEngineByPort(port string) string {
+	switch port {
+	case "3306":
+		return "MySQL"
+	case "5432":
+		return "PostgreSQL"
+	case "1433":
+		return "SQL Server"
+	case "6379":
+		return "Redis"
+	case "11211":
+		return "Memcached"
+	case "1521":
+		return "Oracle"
+	case "27017":
+		return "MongoDB"
+	case "8182":
+		return "Neptune"
+	case "5439":
+		return "Redshift"
+	default:
+		return "Unknown"
+	}
+}
+
+// EngineName returns the display name of an engine, falling back to its port
+// for engines that are only known by their default port
+func EngineName(engine, port string) string {
+	switch strings.ToLower(engine) {
+	case "docdb":
+		return "DocumentDB"
+	case "neptune":
+		return "Neptune"
+	case "redis":
+		return "Redis"
+	case "valkey":
+		return "Valkey"
+	case "memcached":
+		return "Memcached"
+	case "redshift":
+		return "Redshift"
+	case "opensearch":
+		return "OpenSearch"
+	case "elasticsearch":
+		return "Elasticsearch"
+	case "kafka":
+		return "Kafka"
+	default:
+		return DetectEngineByPort(port)
+	}
+}
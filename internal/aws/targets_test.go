@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSortTargets(t *testing.T) {
+	targets := []Target{
+		{Kind: KindElastiCache, Role: "redis-primary", Endpoint: "cache"},
+		{Kind: KindRDS, Role: "member-reader", Cluster: "orders", Endpoint: "orders-2"},
+		{Kind: KindRDSProxy, Role: "proxy-reader", Cluster: "orders-proxy", Endpoint: "proxy-ro"},
+		{Kind: KindRDS, Role: "instance", Endpoint: "billing"},
+		{Kind: KindRDS, Role: "reader", Cluster: "orders", Endpoint: "orders-ro"},
+		{Kind: KindRDSProxy, Role: "proxy", Cluster: "orders-proxy", Endpoint: "proxy"},
+		{Kind: KindRDS, Role: "member-writer", Cluster: "orders", Endpoint: "orders-1"},
+		{Kind: KindRDS, Role: "writer", Cluster: "orders", Endpoint: "orders"},
+		{Kind: KindRDS, Role: "custom", Cluster: "orders", Endpoint: "orders-reporting"},
+	}
+	sortTargets(targets)
+
+	var got []string
+	for _, t := range targets {
+		got = append(got, t.Endpoint)
+	}
+	want := []string{"billing", "orders", "orders-ro", "orders-reporting", "orders-1", "orders-2", "proxy", "proxy-ro", "cache"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+}
+
+func TestWarningsFrom(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []string
+	}{
+		{name: "no error"},
+		{name: "single error", err: errors.New("AccessDenied"), want: []string{"rds: AccessDenied"}},
+		{
+			name: "joined errors",
+			err: errors.Join(
+				fmt.Errorf("DescribeDBClusters: %w", errors.New("AccessDenied")),
+				fmt.Errorf("DescribeDBProxies: %w", errors.New("Throttling")),
+			),
+			want: []string{"rds: DescribeDBClusters: AccessDenied", "rds: DescribeDBProxies: Throttling"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, w := range warningsFrom("rds", tt.err) {
+				got = append(got, w.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("warnings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineName(t *testing.T) {
+	tests := []struct{ engine, port, want string }{
+		{"docdb", "27017", "DocumentDB"},
+		{"aurora-postgresql", "5432", "PostgreSQL"},
+		{"", "3306", "MySQL"},
+		{"valkey", "6379", "Valkey"},
+		{"", "5439", "Redshift"},
+	}
+	for _, tt := range tests {
+		if got := EngineName(tt.engine, tt.port); got != tt.want {
+			t.Errorf("EngineName(%q, %q) = %q, want %q", tt.engine, tt.port, got, tt.want)
+		}
+	}
+}
+
+func TestIsReaderRole(t *testing.T) {
+	for role, want := range map[string]bool{
+		"reader": true, "member-reader": true, "proxy-reader": true, "serverless-reader": true,
+		"replica": true, "redis-replica": true, "valkey-replica": true,
+		"writer": false, "member-writer": false, "proxy": false, "redis-primary": false, "instance": false,
+	} {
+		if got := IsReaderRole(role); got != want {
+			t.Errorf("IsReaderRole(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type entry struct {
+	UpdatedAt time.Time       `json:"updated_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+var cacheDir = filepath.Join(os.Getenv("HOME"), ".aws-ssm-connect", "cache")
+
+// Load decodes the cached value for key into v and reports whether it was found and is younger than ttl
+func Load(key string, ttl time.Duration, v any) (found, fresh bool) {
+	data, err := os.ReadFile(pathFor(key))
+	if err != nil {
+		return false, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false, false
+	}
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return false, false
+	}
+	return true, time.Since(e.UpdatedAt) < ttl
+}
+
+// Save writes v to the cache under key
+func Save(key string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	out, err := json.Marshal(entry{UpdatedAt: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return err
+	}
+
+	// write through a temp file so a concurrent reader never sees a partial entry
+	tmp, err := os.CreateTemp(cacheDir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(out); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), pathFor(key))
+}
+
+// Clear removes all cached entries
+func Clear() error {
+	return os.RemoveAll(cacheDir)
+}
+
+// pathFor maps a cache key to a file name safe for any profile or region name
+func pathFor(key string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	return filepath.Join(cacheDir, safe+".json")
+}
@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/ui"
+)
+
+// confirmedEnv carries a confirmation from a command to the proxy-command it starts through
+// scp, so the user confirms once. The guard protects against mistakes, not against the user.
+const confirmedEnv = "AWS_SSM_CONNECT_CONFIRMED"
+
+// confirmed holds the instances the user has confirmed sessions to during this run,
+// so a command that reaches an instance twice asks once
+var confirmed = map[string]bool{}
+
+// ConfirmedEnv returns the environment entry that passes a confirmation for instanceID to a child
+func ConfirmedEnv(instanceID string) string {
+	return confirmedEnv + "=" + instanceID
+}
+
+// Enforce applies the policy file to a session about to be opened. A protected session must be
+// inside one of the rule's time windows and confirmed by typing the profile name. It returns the
+// longest the session may stay open, or 0 for no limit. An unreadable policy file is an error.
+func Enforce(req Request) (time.Duration, error) {
+	return EnforceAll([]Request{req})
+}
+
+// EnforceAll applies the policy file to sessions on several instances of one profile, asking
+// for a single confirmation. Every matching rule applies: the sessions must be inside all of
+// their windows, and the shortest maximum duration is returned.
+func EnforceAll(reqs []Request) (time.Duration, error) {
+	p, err := Load()
+	if err != nil {
+		return 0, fmt.Errorf("load policy failed: %w", err)
+	}
+	if len(p.Protected) == 0 || len(reqs) == 0 {
+		return 0, nil
+	}
+
+	var (
+		protected   []Request
+		labels      []string
+		maxDuration time.Duration
+		now         = time.Now()
+	)
+	for _, req := range reqs {
+		p.fillRequest(&req)
+		rules := p.Match(req)
+		if len(rules) == 0 {
+			continue
+		}
+		d, err := Check(rules, now)
+		if err != nil {
+			return 0, err
+		}
+		if d > 0 && (maxDuration == 0 || d < maxDuration) {
+			maxDuration = d
+		}
+		protected = append(protected, req)
+		for _, r := range rules {
+			if !slices.Contains(labels, r.Label()) {
+				labels = append(labels, r.Label())
+			}
+		}
+	}
+	if len(protected) == 0 {
+		return 0, nil
+	}
+	if allConfirmed(protected) {
+		return maxDuration, nil
+	}
+
+	profile := protected[0].Profile
+	if len(protected) == 1 {
+		req := protected[0]
+		fmt.Printf("\n🛡️  %s %s (%s) is %s", profile, req.Instance, req.InstanceID, strings.Join(labels, ", "))
+		if req.Target != "" {
+			fmt.Printf(" → %s", req.Target)
+		}
+		fmt.Println()
+	} else {
+		fmt.Printf("\n🛡️  %d of %d instances of %s are %s\n", len(protected), len(reqs), profile, strings.Join(labels, ", "))
+	}
+	if maxDuration > 0 {
+		fmt.Printf("⏱️  The session will be closed after %s\n", maxDuration)
+	}
+
+	if err := ui.ConfirmTyped(fmt.Sprintf("Type %q to continue", profile), profile); err != nil {
+		return 0, fmt.Errorf("%s session not confirmed", strings.Join(labels, ", "))
+	}
+	for _, req := range protected {
+		confirmed[req.InstanceID] = true
+	}
+	return maxDuration, nil
+}
+
+// allConfirmed reports whether every request is to an instance already confirmed in this run
+// or by the parent process
+func allConfirmed(reqs []Request) bool {
+	for _, req := range reqs {
+		if req.InstanceID == "" || !(confirmed[req.InstanceID] || os.Getenv(confirmedEnv) == req.InstanceID) {
+			return false
+		}
+	}
+	return true
+}
+
+// Protected reports whether a session through the instance would be protected, so callers can
+// default to reader endpoints before a target is chosen
+func Protected(profile string, inst aws.Instance) (bool, error) {
+	p, err := Load()
+	if err != nil {
+		return false, fmt.Errorf("load policy failed: %w", err)
+	}
+	if len(p.Protected) == 0 {
+		return false, nil
+	}
+	req := Request{Profile: profile, Instance: inst.Name, InstanceID: inst.ID}
+	p.fillRequest(&req)
+	return len(p.Match(req)) > 0, nil
+}
@@ -0,0 +1,296 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+)
+
+// Policy marks profiles, accounts and instances as protected
+type Policy struct {
+	Protected []Rule `json:"protected"`
+}
+
+// Rule protects every session matching any of its profiles, accounts or tags
+type Rule struct {
+	// Name is shown when the rule applies
+	Name string `json:"name,omitempty"`
+	// Profiles are profile name patterns such as "prod-*"
+	Profiles []string `json:"profiles,omitempty"`
+	// Accounts are AWS account IDs, matched against the caller identity
+	Accounts []string `json:"accounts,omitempty"`
+	// Tags are instance tag value patterns keyed by tag name, such as {"Environment": "prod*"}
+	Tags map[string]string `json:"tags,omitempty"`
+	// Windows limit sessions to these times; no windows means any time
+	Windows []Window `json:"windows,omitempty"`
+	// MaxDuration caps how long a session stays open, e.g. "2h"
+	MaxDuration string `json:"max_duration,omitempty"`
+}
+
+// Window is a daily time range, e.g. Mon-Fri 09:00-18:00; an end before the start spans midnight
+type Window struct {
+	// Days are three-letter weekday names; no days means every day
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+	// Timezone is an IANA zone name; the local zone is used when empty
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Request describes a session about to be opened. An empty Account or nil Tags mean unknown:
+// rules matching on them then protect the session rather than let it through.
+type Request struct {
+	Profile    string
+	Account    string
+	Instance   string
+	InstanceID string
+	// Tags are the instance's current tags; they are looked up when nil and a rule needs them
+	Tags   map[string]string
+	Target string
+}
+
+var policyPath = filepath.Join(os.Getenv("HOME"), ".aws-ssm-connect", "policy.json")
+
+// Load reads the policy file, from AWS_SSM_CONNECT_POLICY if set; no file means nothing is protected
+func Load() (*Policy, error) {
+	file := policyPath
+	if env := os.Getenv("AWS_SSM_CONNECT_POLICY"); env != "" {
+		file = env
+	}
+	data, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		return &Policy{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", file, err)
+	}
+	for i, r := range p.Protected {
+		if _, err := r.Duration(); err != nil {
+			return nil, fmt.Errorf("parse %s: rule %d: %w", file, i+1, err)
+		}
+	}
+	return &p, nil
+}
+
+// Match returns every rule protecting req, in file order
+func (p *Policy) Match(req Request) []Rule {
+	var rules []Rule
+	for _, r := range p.Protected {
+		if r.matches(req) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// Check applies all of rules to a session at now: it must be inside the windows of every rule,
+// and it gets the shortest of their maximum durations, or 0 for no limit
+func Check(rules []Rule, now time.Time) (time.Duration, error) {
+	var maxDuration time.Duration
+	for _, r := range rules {
+		if err := r.Allowed(now); err != nil {
+			return 0, err
+		}
+		if d, _ := r.Duration(); d > 0 && (maxDuration == 0 || d < maxDuration) {
+			maxDuration = d
+		}
+	}
+	return maxDuration, nil
+}
+
+// needsTags reports whether any rule matches on instance tags
+func (p *Policy) needsTags() bool {
+	for _, r := range p.Protected {
+		if len(r.Tags) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// needsAccount reports whether any rule matches on account IDs
+func (p *Policy) needsAccount() bool {
+	for _, r := range p.Protected {
+		if len(r.Accounts) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether the rule protects req, treating an unknown account or unknown tags
+// as a match for rules that look at them
+func (r Rule) matches(req Request) bool {
+	for _, pattern := range r.Profiles {
+		if glob(pattern, req.Profile) {
+			return true
+		}
+	}
+	if len(r.Accounts) > 0 && req.Account == "" {
+		return true
+	}
+	for _, id := range r.Accounts {
+		if id == req.Account {
+			return true
+		}
+	}
+	if len(r.Tags) > 0 && req.Tags == nil {
+		return true
+	}
+	for key, pattern := range r.Tags {
+		if value, ok := req.Tags[key]; ok && glob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// glob matches value against a shell pattern, treating a malformed pattern as a literal
+func glob(pattern, value string) bool {
+	ok, err := path.Match(pattern, value)
+	return ok || (err != nil && pattern == value)
+}
+
+// Label names the rule in messages
+func (r Rule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return "protected"
+}
+
+// Duration returns the rule's maximum session duration, or 0 for none
+func (r Rule) Duration() (time.Duration, error) {
+	if r.MaxDuration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(r.MaxDuration)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid max_duration %q", r.MaxDuration)
+	}
+	return d, nil
+}
+
+// Allowed returns an error unless now falls inside one of the rule's windows
+func (r Rule) Allowed(now time.Time) error {
+	if len(r.Windows) == 0 {
+		return nil
+	}
+	var open []string
+	for _, w := range r.Windows {
+		in, err := w.contains(now)
+		if err != nil {
+			return err
+		}
+		if in {
+			return nil
+		}
+		open = append(open, w.String())
+	}
+	return fmt.Errorf("%s sessions are only allowed %s", r.Label(), strings.Join(open, " or "))
+}
+
+func (w Window) contains(now time.Time) (bool, error) {
+	if w.Timezone != "" {
+		loc, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return false, fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+		}
+		now = now.In(loc)
+	}
+	start, err := minuteOfDay(w.Start)
+	if err != nil {
+		return false, err
+	}
+	end, err := minuteOfDay(w.End)
+	if err != nil {
+		return false, err
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	day := now.Weekday()
+	if end <= start && minute < end {
+		// the early morning part of a window that began the day before
+		day = (day + 6) % 7
+	}
+	if !w.onDay(day) {
+		return false, nil
+	}
+	if end > start {
+		return minute >= start && minute < end, nil
+	}
+	return minute >= start || minute < end, nil
+}
+
+func (w Window) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if strings.EqualFold(d, day.String()[:3]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w Window) String() string {
+	s := w.Start + "-" + w.End
+	if len(w.Days) > 0 {
+		s = strings.Join(w.Days, ",") + " " + s
+	}
+	if w.Timezone != "" {
+		s += " " + w.Timezone
+	}
+	return s
+}
+
+// minuteOfDay parses an HH:MM time
+func minuteOfDay(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window time %q, want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// fillRequest looks up the account and current tags the policy needs but req lacks. Whatever
+// cannot be resolved stays unknown, which makes the rules that need it protect the session.
+func (p *Policy) fillRequest(req *Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if req.Account == "" && p.needsAccount() {
+		arn, err := aws.CallerARN(ctx, req.Profile)
+		if err == nil {
+			req.Account = aws.AccountFromARN(arn)
+		} else {
+			fmt.Fprintf(os.Stderr, "⚠️ Could not determine the AWS account (%v); treating it as protected\n", err)
+		}
+	}
+	if req.Tags == nil && p.needsTags() {
+		switch {
+		case req.InstanceID == "":
+		case aws.IsTaskTarget(req.InstanceID):
+			// ECS tasks carry no instance tags
+			req.Tags = map[string]string{}
+		default:
+			tags, err := aws.FetchInstanceTags(ctx, req.Profile, req.InstanceID)
+			if err == nil {
+				req.Tags = tags
+			} else {
+				fmt.Fprintf(os.Stderr, "⚠️ Could not read the tags of %s (%v); treating it as protected\n", req.InstanceID, err)
+			}
+		}
+	}
+}
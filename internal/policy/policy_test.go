@@ -0,0 +1,133 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatch(t *testing.T) {
+	p := &Policy{Protected: []Rule{
+		{Name: "prod", Profiles: []string{"prod-*"}, Accounts: []string{"111111111111"}},
+		{Name: "pci", Tags: map[string]string{"Environment": "pci*"}},
+	}}
+	known := map[string]string{}
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{"profile pattern", Request{Profile: "prod-admin", Account: "222222222222", Tags: known}, "prod"},
+		{"account", Request{Profile: "ops", Account: "111111111111", Tags: known}, "prod"},
+		{"tag pattern", Request{Profile: "dev", Account: "222222222222", Tags: map[string]string{"Environment": "pci-eu"}}, "pci"},
+		{"other tag value", Request{Profile: "dev", Account: "222222222222", Tags: map[string]string{"Environment": "dev"}}, ""},
+		{"unprotected", Request{Profile: "dev", Account: "222222222222", Tags: known}, ""},
+		{"unknown account fails closed", Request{Profile: "dev", Tags: known}, "prod"},
+		{"unknown tags fail closed", Request{Profile: "dev", Account: "222222222222"}, "pci"},
+		{"every matching rule", Request{Profile: "prod-admin", Account: "222222222222", Tags: map[string]string{"Environment": "pci-eu"}}, "prod,pci"},
+	}
+	for _, tt := range tests {
+		var names []string
+		for _, r := range p.Match(tt.req) {
+			names = append(names, r.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("%s: Match = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckAppliesEveryRule(t *testing.T) {
+	p := &Policy{Protected: []Rule{
+		{Name: "prod", Profiles: []string{"prod-*"}, MaxDuration: "4h"},
+		{
+			Name:        "office",
+			Accounts:    []string{"111111111111"},
+			Windows:     []Window{{Start: "09:00", End: "18:00", Timezone: "UTC"}},
+			MaxDuration: "1h",
+		},
+	}}
+	req := Request{Profile: "prod-admin", Account: "111111111111", Tags: map[string]string{}}
+	rules := p.Match(req)
+	if len(rules) != 2 {
+		t.Fatalf("Match returned %d rules, want 2", len(rules))
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		want    time.Duration
+		wantErr bool
+	}{
+		{"inside both", time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC), time.Hour, false},
+		{"second rule blocks", time.Date(2026, 10, 16, 20, 0, 0, 0, time.UTC), 0, true},
+	}
+	for _, tt := range tests {
+		got, err := Check(rules, tt.now)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: Check = %v, %v; want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	office := Rule{Windows: []Window{{Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "09:00", End: "18:00", Timezone: "UTC"}}}
+	night := Rule{Windows: []Window{{Days: []string{"Sat"}, Start: "22:00", End: "02:00", Timezone: "UTC"}}}
+	at := func(day, hhmm string) time.Time {
+		tm, err := time.Parse("2006-01-02 15:04", day+" "+hhmm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+	// 2026-10-16 is a Friday
+	tests := []struct {
+		name string
+		rule Rule
+		now  time.Time
+		want bool
+	}{
+		{"office hours", office, at("2026-10-16", "10:30"), true},
+		{"end is exclusive", office, at("2026-10-16", "18:00"), false},
+		{"weekend", office, at("2026-10-17", "10:30"), false},
+		{"overnight start", night, at("2026-10-17", "23:00"), true},
+		{"overnight after midnight", night, at("2026-10-18", "01:30"), true},
+		{"overnight wrong day", night, at("2026-10-17", "01:30"), false},
+		{"no windows", Rule{}, at("2026-10-18", "03:00"), true},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Allowed(tt.now) == nil; got != tt.want {
+			t.Errorf("%s: allowed = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.json")
+	t.Setenv("AWS_SSM_CONNECT_POLICY", file)
+
+	p, err := Load()
+	if err != nil || len(p.Protected) != 0 {
+		t.Fatalf("missing file: got %+v, %v; want empty policy", p, err)
+	}
+
+	if err := os.WriteFile(file, []byte(`{"protected":[{"profiles":["prod"],"max_duration":"2h"}]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, err = Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if d, _ := p.Protected[0].Duration(); d != 2*time.Hour {
+		t.Errorf("max duration = %v, want 2h", d)
+	}
+
+	if err := os.WriteFile(file, []byte(`{"protected":[{"profiles":["prod"],"max_duration":"forever"}]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("Load accepted an invalid max_duration")
+	}
+}
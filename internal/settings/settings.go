@@ -0,0 +1,95 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Settings holds user preferences read from ~/.aws-ssm-connect/config.json
+type Settings struct {
+	// Providers turns discovery providers on or off by name; providers not listed are enabled
+	Providers map[string]bool `json:"providers,omitempty"`
+	// Endpoints overrides service endpoints by service ID, e.g. for LocalStack or moto;
+	// the "default" entry applies to services without their own entry
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+	// Session configures --ssm shell sessions
+	Session SessionSettings `json:"session,omitempty"`
+	// Audit configures where tunnel and shell audit records are sent besides the local log
+	Audit AuditSettings `json:"audit,omitempty"`
+}
+
+// AuditSettings configures the optional webhook sink of the audit log
+type AuditSettings struct {
+	// Webhook receives every audit record as a JSON POST
+	Webhook string `json:"webhook,omitempty"`
+	// Headers are added to webhook requests, e.g. an Authorization token
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Session describes how a shell session is started; empty fields fall back to less specific settings
+type Session struct {
+	// Document is the Session Manager document to start, e.g. an org document enforcing logging
+	Document string `json:"document,omitempty"`
+	// RunAs is the OS user the shell runs as
+	RunAs string `json:"run_as,omitempty"`
+	// Command is the shell or initial command to run instead of the document's default shell
+	Command string `json:"command,omitempty"`
+}
+
+// SessionSettings holds session defaults with overrides per profile and per instance ID or Name tag
+type SessionSettings struct {
+	Session
+	Profiles  map[string]Session `json:"profiles,omitempty"`
+	Instances map[string]Session `json:"instances,omitempty"`
+}
+
+var settingsPath = filepath.Join(os.Getenv("HOME"), ".aws-ssm-connect", "config.json")
+
+// Load reads the settings file, returning empty settings if it does not exist
+func Load() (*Settings, error) {
+	data, err := os.ReadFile(settingsPath)
+	if os.IsNotExist(err) {
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", settingsPath, err)
+	}
+	return &s, nil
+}
+
+// ProviderEnabled reports whether the named discovery provider should run
+func (s *Settings) ProviderEnabled(name string) bool {
+	enabled, ok := s.Providers[name]
+	return !ok || enabled
+}
+
+// SessionFor resolves the session settings for an instance: instance ID entries win over
+// Name tag entries, which win over the profile entry and then the defaults
+func (s *Settings) SessionFor(profile, instanceID, instanceName string) Session {
+	sess := s.Session.Session
+	sess = sess.Merge(s.Session.Profiles[profile])
+	if instanceName != "" {
+		sess = sess.Merge(s.Session.Instances[instanceName])
+	}
+	return sess.Merge(s.Session.Instances[instanceID])
+}
+
+// Merge returns s with the non-empty fields of o applied on top
+func (s Session) Merge(o Session) Session {
+	if o.Document != "" {
+		s.Document = o.Document
+	}
+	if o.RunAs != "" {
+		s.RunAs = o.RunAs
+	}
+	if o.Command != "" {
+		s.Command = o.Command
+	}
+	return s
+}
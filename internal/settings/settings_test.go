@@ -0,0 +1,41 @@
+package settings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSessionFor(t *testing.T) {
+	var s Settings
+	err := json.Unmarshal([]byte(`{
+		"session": {
+			"document": "Org-SessionManagerRunShell",
+			"profiles": {"prod": {"run_as": "ops"}},
+			"instances": {
+				"legacy-box": {"command": "sh"},
+				"i-0abc": {"run_as": "admin"}
+			}
+		}
+	}`), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                      string
+		profile, id, instanceName string
+		want                      Session
+	}{
+		{name: "defaults", profile: "dev", id: "i-1", want: Session{Document: "Org-SessionManagerRunShell"}},
+		{name: "profile", profile: "prod", id: "i-1", want: Session{Document: "Org-SessionManagerRunShell", RunAs: "ops"}},
+		{name: "name tag", profile: "prod", id: "i-1", instanceName: "legacy-box", want: Session{Document: "Org-SessionManagerRunShell", RunAs: "ops", Command: "sh"}},
+		{name: "instance ID wins", profile: "prod", id: "i-0abc", instanceName: "legacy-box", want: Session{Document: "Org-SessionManagerRunShell", RunAs: "admin", Command: "sh"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.SessionFor(tt.profile, tt.id, tt.instanceName); got != tt.want {
+				t.Errorf("SessionFor = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"net"
 	"os"
-	"os/exec"
 	"syscall"
+	"time"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/audit"
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/policy"
 )
 
 var CurrentPid int // exported for cleanup handling
@@ -16,16 +20,80 @@ func StartPortForward(profile, instanceName, instanceID, remoteHost, remotePort,
 		return fmt.Errorf("❌ Local port %s is already in use", localPort)
 	}
 
-	fmt.Printf("\n✅ Starting port-forward:\n💻 localhost:%s → 🖥️ %s (%s) → 🛢️ %s:%s\n\n",
-		localPort, instanceName, instanceID, remoteHost, remotePort)
+	return startSession(PIDInfo{
+		Profile:    profile,
+		Instance:   instanceName,
+		InstanceID: instanceID,
+		DB:         fmt.Sprintf("%s:%s", remoteHost, localPort),
+		Target:     net.JoinHostPort(remoteHost, remotePort),
+		LocalPort:  localPort,
+	},
+		fmt.Sprintf("💻 localhost:%s → 🖥️ %s (%s) → 🛢️ %s:%s", localPort, instanceName, instanceID, remoteHost, remotePort),
+		"AWS-StartPortForwardingSessionToRemoteHost",
+		fmt.Sprintf("host=[\"%s\"],portNumber=[\"%s\"]", remoteHost, remotePort),
+	)
+}
+
+// StartInstancePortForward spawns a background SSM port-forward session to a port on the instance itself
+func StartInstancePortForward(profile, instanceName, instanceID, remotePort, localPort string) error {
+	if isPortInUse(localPort) {
+		return fmt.Errorf("❌ Local port %s is already in use", localPort)
+	}
 
-	cmd := exec.Command(
-		"aws", "ssm", "start-session",
-		"--profile", profile,
-		"--target", instanceID,
-		"--document-name", "AWS-StartPortForwardingSessionToRemoteHost",
-		"--parameters", fmt.Sprintf("host=[\"%s\"],portNumber=[\"%s\"],localPortNumber=[\"%s\"]", remoteHost, remotePort, localPort),
+	return startSession(PIDInfo{
+		Profile:    profile,
+		Instance:   instanceName,
+		InstanceID: instanceID,
+		DB:         fmt.Sprintf("instance:%s → %s", remotePort, localPort),
+		Target:     "instance:" + remotePort,
+		LocalPort:  localPort,
+	},
+		fmt.Sprintf("💻 localhost:%s → 🖥️ %s (%s):%s", localPort, instanceName, instanceID, remotePort),
+		"AWS-StartPortForwardingSession",
+		fmt.Sprintf("portNumber=[\"%s\"]", remotePort),
 	)
+}
+
+// startSession runs an SSM port-forwarding document in the background for the session described
+// by info once the policy file allows it, records it in pids.json and writes its start to the audit log.
+// The session runs under a supervisor that serves the local port itself, records when the session
+// ends and closes it when a TTL, idle timeout or policy maximum duration is reached.
+func startSession(info PIDInfo, route, document, parameters string) error {
+	info.Caller = audit.Caller(info.Profile)
+	maxDuration, err := policy.Enforce(policy.Request{
+		Profile:    info.Profile,
+		Account:    aws.AccountFromARN(info.Caller),
+		Instance:   info.Instance,
+		InstanceID: info.InstanceID,
+		Target:     info.Target,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n✅ Starting port-forward:\n%s\n\n", route)
+
+	l := limits
+	if maxDuration > 0 && (l.TTL == 0 || maxDuration < l.TTL) {
+		l.TTL = maxDuration
+	}
+
+	sessionArgs := func(localPort string) []string {
+		return []string{
+			"ssm", "start-session",
+			"--profile", info.Profile,
+			"--target", info.InstanceID,
+			"--document-name", document,
+			"--parameters", fmt.Sprintf("%s,localPortNumber=[\"%s\"]", parameters, localPort),
+		}
+	}
+	upstream, err := freePort()
+	if err != nil {
+		return fmt.Errorf("failed to pick an internal port: %w", err)
+	}
+	cmd, err := supervisorCommand(info.LocalPort, upstream, l, sessionArgs(upstream))
+	if err != nil {
+		return err
+	}
 
 	// run in background silently
 	null, _ := os.OpenFile(os.DevNull, os.O_RDWR, 0)
@@ -40,14 +108,24 @@ func StartPortForward(profile, instanceName, instanceID, remoteHost, remotePort,
 
 	CurrentPid = cmd.Process.Pid
 
-	_ = SavePID(PIDInfo{
-		PID:      CurrentPid,
-		Profile:  profile,
-		Instance: instanceName,
-		DB:       fmt.Sprintf("%s:%s", remoteHost, localPort),
-	})
+	info.PID = CurrentPid
+	info.Started = time.Now().UTC()
+	if l.TTL > 0 {
+		info.Expires = info.Started.Add(l.TTL)
+	}
+	if l.IdleTimeout > 0 {
+		info.IdleTimeout = l.IdleTimeout.String()
+	}
+	_ = SavePID(info)
+	audit.Log(info.auditRecord(audit.EventStart))
 
 	fmt.Printf("🔵 Port-forward started in background (PID %d)\n", CurrentPid)
+	if l.TTL > 0 {
+		fmt.Printf("⏱️ Closes after %s\n", l.TTL)
+	}
+	if l.IdleTimeout > 0 {
+		fmt.Printf("💤 Closes after %s without traffic\n", l.IdleTimeout)
+	}
 	return nil
 }
 
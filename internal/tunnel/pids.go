@@ -2,59 +2,150 @@ package tunnel
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/audit"
 )
 
 type PIDInfo struct {
-	PID      int    `json:"pid"`
-	Profile  string `json:"profile"`
-	Instance string `json:"instance"`
-	DB       string `json:"db"`
+	PID        int       `json:"pid"`
+	Profile    string    `json:"profile"`
+	Instance   string    `json:"instance"`
+	DB         string    `json:"db"`
+	InstanceID string    `json:"instance_id,omitempty"`
+	Target     string    `json:"target,omitempty"`
+	LocalPort  string    `json:"local_port,omitempty"`
+	Caller     string    `json:"caller,omitempty"`
+	Started    time.Time `json:"started,omitzero"`
+	Expires    time.Time `json:"expires,omitzero"`
+	// IdleTimeout is the supervisor's idle timeout, e.g. "30m"
+	IdleTimeout string `json:"idle_timeout,omitempty"`
 }
 
-var pidsFilePath = filepath.Join(os.Getenv("HOME"), ".aws-ssm-connect", "pids.json")
+// auditRecord describes the session for the audit log
+func (p PIDInfo) auditRecord(event string) audit.Record {
+	return audit.Record{
+		Event:      event,
+		Kind:       "port-forward",
+		Caller:     p.Caller,
+		Profile:    p.Profile,
+		Instance:   p.Instance,
+		InstanceID: p.InstanceID,
+		Target:     p.Target,
+		LocalPort:  p.LocalPort,
+		PID:        p.PID,
+		Started:    p.Started,
+	}
+}
 
-// SavePID appends a new PID record to pids.json
-func SavePID(info PIDInfo) error {
-	var existing []PIDInfo
+// recordStop writes the end of a session, stopped now, to the audit log
+func recordStop(p PIDInfo, reason string) {
+	rec := p.auditRecord(audit.EventStop)
+	rec.Stopped = time.Now().UTC()
+	rec.Reason = reason
+	audit.Log(rec)
+}
 
-	data, err := os.ReadFile(pidsFilePath)
-	if err == nil {
-		_ = json.Unmarshal(data, &existing)
+// recordLost writes the end of a session whose process was found gone. Its supervisor records
+// every exit it sees, so the session ended without one (e.g. a reboot) at an unknown time,
+// which the record leaves out rather than guess.
+func recordLost(p PIDInfo) {
+	rec := p.auditRecord(audit.EventStop)
+	rec.Reason = "exited; end time unknown"
+	audit.Log(rec)
+}
+
+// killGroup kills a session's process group, reporting whether it was still running
+func killGroup(pid int) (bool, error) {
+	err := syscall.Kill(-pid, syscall.SIGKILL)
+	if errors.Is(err, syscall.ESRCH) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to kill pid %d: %w", pid, err)
 	}
+	return true, nil
+}
 
-	existing = append(existing, info)
-	out, _ := json.MarshalIndent(existing, "", "  ")
+var pidsFilePath = filepath.Join(os.Getenv("HOME"), ".aws-ssm-connect", "pids.json")
 
-	_ = os.MkdirAll(filepath.Dir(pidsFilePath), 0700)
-	return os.WriteFile(pidsFilePath, out, 0600)
-}
+// updatePIDs rewrites pids.json with change under an exclusive lock, as supervisors and the
+// CLI update it at the same time
+func updatePIDs(change func([]PIDInfo) []PIDInfo) error {
+	if err := os.MkdirAll(filepath.Dir(pidsFilePath), 0700); err != nil {
+		return err
+	}
+	lock, err := os.OpenFile(pidsFilePath+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open pids lock: %w", err)
+	}
+	defer lock.Close()
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("could not lock pids file: %w", err)
+	}
+	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN)
 
-// ListPIDs prints alive PIDs and cleans up dead ones
-func ListPIDs() error {
+	var pids []PIDInfo
 	data, err := os.ReadFile(pidsFilePath)
-	if err != nil {
+	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("could not read pids file: %w", err)
 	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &pids); err != nil {
+			return fmt.Errorf("could not parse pids file: %w", err)
+		}
+	}
 
-	var pids []PIDInfo
-	if err := json.Unmarshal(data, &pids); err != nil {
-		return fmt.Errorf("could not parse pids file: %w", err)
+	out, _ := json.MarshalIndent(change(pids), "", "  ")
+	// write through a temp file so a reader never sees a partial list
+	tmp, err := os.CreateTemp(filepath.Dir(pidsFilePath), ".pids-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(out); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
 	}
+	return os.Rename(tmp.Name(), pidsFilePath)
+}
 
-	var alive []PIDInfo
-	for _, p := range pids {
-		if processExists(p.PID) {
-			alive = append(alive, p)
+// SavePID appends a new PID record to pids.json
+func SavePID(info PIDInfo) error {
+	return updatePIDs(func(pids []PIDInfo) []PIDInfo {
+		return append(pids, info)
+	})
+}
+
+// ListPIDs prints alive PIDs and cleans up dead ones
+func ListPIDs() error {
+	var alive, dead []PIDInfo
+	err := updatePIDs(func(pids []PIDInfo) []PIDInfo {
+		for _, p := range pids {
+			if processExists(p.PID) {
+				alive = append(alive, p)
+			} else {
+				dead = append(dead, p)
+			}
 		}
+		return alive
+	})
+	if err != nil {
+		return err
+	}
+	for _, p := range dead {
+		recordLost(p)
 	}
-
-	out, _ := json.MarshalIndent(alive, "", "  ")
-	_ = os.WriteFile(pidsFilePath, out, 0600)
 
 	if len(alive) == 0 {
 		fmt.Println("No active port-forward sessions.")
@@ -63,68 +154,107 @@ func ListPIDs() error {
 
 	fmt.Println("Active Port-Forward Sessions:")
 	for _, p := range alive {
-		fmt.Printf("🔵 PID: %d | Profile: %s | Instance: %s | DB: %s\n", p.PID, p.Profile, p.Instance, p.DB)
+		line := fmt.Sprintf("🔵 PID: %d | Profile: %s | Instance: %s | DB: %s", p.PID, p.Profile, p.Instance, p.DB)
+		if !p.Expires.IsZero() {
+			line += fmt.Sprintf(" | ⏱️ %s left", remaining(p.Expires, time.Now()))
+		}
+		if p.IdleTimeout != "" {
+			line += fmt.Sprintf(" | 💤 idle %s", p.IdleTimeout)
+		}
+		fmt.Println(line)
 	}
 	return nil
 }
 
+// remaining formats the time left until expires, to the minute once it is over a minute
+func remaining(expires, now time.Time) string {
+	left := expires.Sub(now)
+	if left <= 0 {
+		return "0s"
+	}
+	if left < time.Minute {
+		return left.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(left.Round(time.Minute).String(), "0s")
+}
+
 // KillPID kills a specific session by PID and removes it from file
 func KillPID(pid int) error {
 	fmt.Printf("🛑 Attempting to kill PID %d...\n", pid)
+	return StopSession(pid, "killed")
+}
 
-	err := syscall.Kill(-pid, syscall.SIGKILL)
-	if err != nil && !strings.Contains(err.Error(), "no such process") {
-		return fmt.Errorf("failed to kill pid %d: %w", pid, err)
+// StopSession kills a session, removes it from file and records reason in the audit log
+func StopSession(pid int, reason string) error {
+	running, err := killGroup(pid)
+	if err != nil {
+		return err
 	}
 
-	data, _ := os.ReadFile(pidsFilePath)
-	var pids []PIDInfo
-	_ = json.Unmarshal(data, &pids)
-
-	var updated []PIDInfo
-	for _, p := range pids {
-		if p.PID != pid {
-			updated = append(updated, p)
+	if p, ok := removePID(pid); ok {
+		if running {
+			recordStop(p, reason)
+		} else {
+			recordLost(p)
 		}
 	}
+	return nil
+}
 
-	out, _ := json.MarshalIndent(updated, "", "  ")
-	return os.WriteFile(pidsFilePath, out, 0600)
+// removePID drops a session from pids.json, returning its record if it was there
+func removePID(pid int) (PIDInfo, bool) {
+	var (
+		removed PIDInfo
+		found   bool
+	)
+	_ = updatePIDs(func(pids []PIDInfo) []PIDInfo {
+		var updated []PIDInfo
+		for _, p := range pids {
+			if p.PID != pid {
+				updated = append(updated, p)
+			} else {
+				removed, found = p, true
+			}
+		}
+		return updated
+	})
+	return removed, found
 }
 
 // KillAllPIDs kills all sessions and clears pids.json
 func KillAllPIDs() error {
 	fmt.Println("🛑 Attempting to kill all active port-forward sessions...")
 
-	data, err := os.ReadFile(pidsFilePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			fmt.Println("No active sessions to kill.")
-			return nil
+	var killed, lost []PIDInfo
+	err := updatePIDs(func(pids []PIDInfo) []PIDInfo {
+		for _, p := range pids {
+			running, err := killGroup(p.PID)
+			switch {
+			case err != nil:
+				fmt.Printf("❌ Failed to kill PID %d: %v\n", p.PID, err)
+			case running:
+				fmt.Printf("✅ Killed PID %d\n", p.PID)
+				killed = append(killed, p)
+			default:
+				lost = append(lost, p)
+			}
 		}
-		return fmt.Errorf("could not read pids file: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-
-	var pids []PIDInfo
-	_ = json.Unmarshal(data, &pids)
-
-	killed := 0
-	for _, p := range pids {
-		err := syscall.Kill(-p.PID, syscall.SIGKILL)
-		if err != nil && !strings.Contains(err.Error(), "no such process") {
-			fmt.Printf("❌ Failed to kill PID %d: %v\n", p.PID, err)
-		} else {
-			fmt.Printf("✅ Killed PID %d\n", p.PID)
-			killed++
-		}
+	for _, p := range killed {
+		recordStop(p, "killed")
+	}
+	for _, p := range lost {
+		recordLost(p)
 	}
 
-	_ = os.Remove(pidsFilePath)
-
-	if killed == 0 {
+	if len(killed) == 0 {
 		fmt.Println("ℹ️ No alive sessions were found.")
 	} else {
-		fmt.Printf("🔵 Successfully killed %d sessions.\n", killed)
+		fmt.Printf("🔵 Successfully killed %d sessions.\n", len(killed))
 	}
 	return nil
 }
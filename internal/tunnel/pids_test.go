@@ -0,0 +1,62 @@
+package tunnel
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestUpdatePIDsConcurrent(t *testing.T) {
+	old := pidsFilePath
+	pidsFilePath = filepath.Join(t.TempDir(), "pids.json")
+	defer func() { pidsFilePath = old }()
+
+	for pid := 1; pid <= 10; pid++ {
+		if err := SavePID(PIDInfo{PID: pid}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// half the sessions end while as many new ones start
+	var wg sync.WaitGroup
+	for pid := 1; pid <= 10; pid++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if pid%2 == 0 {
+				if _, ok := removePID(pid); !ok {
+					t.Errorf("removePID(%d) found nothing", pid)
+				}
+			} else if err := SavePID(PIDInfo{PID: pid + 100}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(pidsFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pids []PIDInfo
+	if err := json.Unmarshal(data, &pids); err != nil {
+		t.Fatal(err)
+	}
+	got := map[int]bool{}
+	for _, p := range pids {
+		got[p.PID] = true
+	}
+	for pid := 1; pid <= 10; pid++ {
+		if got[pid] == (pid%2 == 0) {
+			t.Errorf("pid %d present = %v after the updates", pid, got[pid])
+		}
+		if pid%2 == 1 && !got[pid+100] {
+			t.Errorf("pid %d was lost", pid+100)
+		}
+	}
+	if len(pids) != 10 {
+		t.Errorf("pids.json has %d entries, want 10", len(pids))
+	}
+}
@@ -0,0 +1,42 @@
+package tunnel
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// SSHSessionCommand streams an SSH connection to the instance over stdio using AWS-StartSSHSession.
+// stdout is passed explicitly because ssh reads the session from it.
+func SSHSessionCommand(profile, instanceID, port string, stdout *os.File) *exec.Cmd {
+	cmd := exec.Command(
+		"aws", "ssm", "start-session",
+		"--profile", profile,
+		"--target", instanceID,
+		"--document-name", "AWS-StartSSHSession",
+		"--parameters", "portNumber="+port,
+	)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	return cmd
+}
+
+// SCPCommand runs scp with this binary as the SSH-over-SSM ProxyCommand and the throwaway key as identity,
+// adding env to the environment of both. scp draws its own progress meter when attached to a terminal.
+func SCPCommand(profile, src, dst string, env ...string) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command("scp",
+		"-o", fmt.Sprintf("ProxyCommand=%q --profile %q proxy-command %%h %%p", self, profile),
+		"-o", "IdentityFile="+SSHKeyPath,
+		src, dst,
+	)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd, nil
+}
@@ -0,0 +1,33 @@
+package tunnel
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// SSHKeyPath is the private half of the keypair pushed with EC2 Instance Connect
+var SSHKeyPath = filepath.Join(os.Getenv("HOME"), ".aws-ssm-connect", "ssh", "id_ed25519")
+
+// EnsureSSHKey returns the public key to push, generating a throwaway keypair with ssh-keygen on first use.
+// The key is only ever authorized for 60 seconds at a time, so it is not protected by a passphrase.
+func EnsureSSHKey() (string, error) {
+	if _, err := os.Stat(SSHKeyPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(SSHKeyPath), 0700); err != nil {
+			return "", err
+		}
+		cmd := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "aws-ssm-connect", "-f", SSHKeyPath)
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("ssh-keygen failed: %w", err)
+		}
+	}
+
+	pub, err := os.ReadFile(SSHKeyPath + ".pub")
+	if err != nil {
+		return "", fmt.Errorf("read public key failed: %w", err)
+	}
+	return strings.TrimSpace(string(pub)), nil
+}
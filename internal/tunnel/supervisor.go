@@ -0,0 +1,224 @@
+package tunnel
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Limits bound how long a background tunnel stays open; zero values mean no limit
+type Limits struct {
+	TTL         time.Duration
+	IdleTimeout time.Duration
+}
+
+var limits Limits
+
+// SetLimits applies a TTL and idle timeout to the port-forwards started afterwards
+func SetLimits(l Limits) {
+	limits = l
+}
+
+// upstreamDialTimeout is how long a connection waits for the SSM plugin's listener to come up
+var upstreamDialTimeout = 30 * time.Second
+
+// watchdog decides when a supervised tunnel has expired
+type watchdog struct {
+	started time.Time
+	ttl     time.Duration
+	idle    time.Duration
+	// last is when bytes last moved through the tunnel, in Unix nanoseconds
+	last atomic.Int64
+}
+
+func newWatchdog(now time.Time, ttl, idle time.Duration) *watchdog {
+	w := &watchdog{started: now, ttl: ttl, idle: idle}
+	w.last.Store(now.UnixNano())
+	return w
+}
+
+// touch records traffic
+func (w *watchdog) touch(now time.Time) {
+	w.last.Store(now.UnixNano())
+}
+
+// expired returns why the tunnel should close at now, or "" while it may stay open
+func (w *watchdog) expired(now time.Time) string {
+	if w.ttl > 0 && now.Sub(w.started) >= w.ttl {
+		return "ttl expired"
+	}
+	if w.idle > 0 && now.Sub(time.Unix(0, w.last.Load())) >= w.idle {
+		return "idle timeout"
+	}
+	return ""
+}
+
+// Supervise is the detached process behind every background tunnel. It runs the aws ssm
+// start-session command given after "--" with the plugin listening on an internal port, serves
+// the user's local port itself, and records the session's end when it exits, a limit is reached or
+// it is terminated:
+//
+//	aws-ssm-connect supervise --listen 5432 --upstream 49152 --ttl 2h --idle-timeout 30m -- aws ssm start-session ...
+func Supervise(args []string) error {
+	fs := flag.NewFlagSet("supervise", flag.ExitOnError)
+	listen := fs.String("listen", "", "Local port to serve")
+	upstream := fs.String("upstream", "", "Port the session manager plugin listens on")
+	ttl := fs.Duration("ttl", 0, "Close the tunnel after this long")
+	idle := fs.Duration("idle-timeout", 0, "Close the tunnel after this long without traffic")
+	_ = fs.Parse(args)
+	if *listen == "" || *upstream == "" || fs.NArg() == 0 {
+		return fmt.Errorf("usage: aws-ssm-connect supervise --listen <port> --upstream <port> [--ttl d] [--idle-timeout d] -- <command>")
+	}
+
+	// trap termination before the session starts so it can never outlive us unrecorded
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:"+*listen)
+	if err != nil {
+		return fmt.Errorf("listen failed: %w", err)
+	}
+	defer ln.Close()
+
+	session := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	if err := session.Start(); err != nil {
+		return fmt.Errorf("failed to start port forward: %w", err)
+	}
+	exited := make(chan struct{})
+	go func() {
+		_ = session.Wait()
+		close(exited)
+	}()
+
+	w := newWatchdog(time.Now(), *ttl, *idle)
+	go serve(ln, "127.0.0.1:"+*upstream, w)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	reason := waitForEnd(exited, stop, ticker.C, w)
+
+	// a session that fails at once can end before the parent has saved its record
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(100 * time.Millisecond) {
+		if p, ok := removePID(os.Getpid()); ok {
+			recordStop(p, reason)
+			break
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+	}
+	// the session manager plugin shares our process group
+	return syscall.Kill(-os.Getpid(), syscall.SIGKILL)
+}
+
+// waitForEnd blocks until the session exits, a signal arrives or the watchdog expires, and returns why
+func waitForEnd(exited <-chan struct{}, stop <-chan os.Signal, tick <-chan time.Time, w *watchdog) string {
+	for {
+		select {
+		case <-exited:
+			return "exited"
+		case <-stop:
+			return "terminated"
+		case now := <-tick:
+			if reason := w.expired(now); reason != "" {
+				return reason
+			}
+		}
+	}
+}
+
+// serve accepts local connections and proxies each to the plugin's listener
+func serve(ln net.Listener, upstream string, w *watchdog) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go proxy(conn, upstream, w)
+	}
+}
+
+// proxy copies bytes between a local connection and the plugin, touching w on every transfer
+func proxy(conn net.Conn, upstream string, w *watchdog) {
+	defer conn.Close()
+	w.touch(time.Now())
+
+	// the plugin opens its listener a few seconds after the session starts
+	var remote net.Conn
+	deadline := time.Now().Add(upstreamDialTimeout)
+	for {
+		var err error
+		remote, err = net.DialTimeout("tcp", upstream, time.Second)
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	defer remote.Close()
+
+	done := make(chan struct{}, 2)
+	pipe := func(dst, src net.Conn) {
+		_, _ = io.Copy(dst, activityReader{src, w})
+		if tcp, ok := dst.(*net.TCPConn); ok {
+			_ = tcp.CloseWrite()
+		}
+		done <- struct{}{}
+	}
+	go pipe(remote, conn)
+	go pipe(conn, remote)
+	<-done
+	<-done
+}
+
+// activityReader touches the watchdog whenever data is read
+type activityReader struct {
+	r io.Reader
+	w *watchdog
+}
+
+func (a activityReader) Read(p []byte) (int, error) {
+	n, err := a.r.Read(p)
+	if n > 0 {
+		a.w.touch(time.Now())
+	}
+	return n, err
+}
+
+// supervisorCommand wraps the aws ssm start-session arguments in a supervise invocation of this binary
+func supervisorCommand(listen, upstream string, l Limits, sessionArgs []string) (*exec.Cmd, error) {
+	self, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locate executable failed: %w", err)
+	}
+	args := []string{"supervise", "--listen", listen, "--upstream", upstream}
+	if l.TTL > 0 {
+		args = append(args, "--ttl", l.TTL.String())
+	}
+	if l.IdleTimeout > 0 {
+		args = append(args, "--idle-timeout", l.IdleTimeout.String())
+	}
+	args = append(args, "--", "aws")
+	args = append(args, sessionArgs...)
+	return exec.Command(self, args...), nil
+}
+
+// freePort asks the kernel for an unused local port
+func freePort() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer ln.Close()
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	return port, err
+}
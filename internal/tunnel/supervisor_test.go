@@ -0,0 +1,123 @@
+package tunnel
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatchdogExpired(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		ttl, idle time.Duration
+		activity  time.Duration
+		now       time.Duration
+		want      string
+	}{
+		{"no limits", 0, 0, 0, 48 * time.Hour, ""},
+		{"within ttl", 2 * time.Hour, 0, 0, time.Hour, ""},
+		{"ttl reached", 2 * time.Hour, 0, 0, 2 * time.Hour, "ttl expired"},
+		{"idle", 0, 30 * time.Minute, 0, 31 * time.Minute, "idle timeout"},
+		{"recent traffic", 0, 30 * time.Minute, 20 * time.Minute, 45 * time.Minute, ""},
+		{"ttl wins over traffic", time.Hour, 30 * time.Minute, 59 * time.Minute, time.Hour, "ttl expired"},
+	}
+	for _, tt := range tests {
+		w := newWatchdog(start, tt.ttl, tt.idle)
+		if tt.activity > 0 {
+			w.touch(start.Add(tt.activity))
+		}
+		if got := w.expired(start.Add(tt.now)); got != tt.want {
+			t.Errorf("%s: expired = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProxyTouchesWatchdog(t *testing.T) {
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer echo.Close()
+	go func() {
+		for {
+			conn, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	past := time.Now().Add(-time.Hour)
+	w := newWatchdog(past, 0, time.Minute)
+	go serve(ln, echo.Addr().String(), w)
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("ping\n")); err != nil {
+		t.Fatal(err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || line != "ping\n" {
+		t.Fatalf("echo = %q, %v; want ping", line, err)
+	}
+	if reason := w.expired(time.Now()); reason != "" {
+		t.Errorf("expired = %q after traffic, want open", reason)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	tests := map[time.Duration]string{
+		-time.Minute:                    "0s",
+		42 * time.Second:                "42s",
+		90*time.Minute + 20*time.Second: "1h30m",
+		2 * time.Hour:                   "2h0m",
+	}
+	for left, want := range tests {
+		if got := remaining(now.Add(left), now); got != want {
+			t.Errorf("remaining(%v) = %q, want %q", left, got, want)
+		}
+	}
+}
+
+func TestWaitForEndOnSIGTERM(t *testing.T) {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	exited := make(chan struct{})
+	tick := make(chan time.Time)
+	w := newWatchdog(time.Now(), 0, 0)
+
+	done := make(chan string, 1)
+	go func() { done <- waitForEnd(exited, stop, tick, w) }()
+	select {
+	case reason := <-done:
+		if reason != "terminated" {
+			t.Errorf("reason = %q, want terminated", reason)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("supervisor did not stop on SIGTERM")
+	}
+}
@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+)
+
+// caBundleURL is the RDS global certificate bundle, also used by DocumentDB and Neptune
+const caBundleURL = "https://truststore.pki.rds.amazonaws.com/global/global-bundle.pem"
+
+// ConnectionHint returns an example client command for a tunneled target, or "" if none applies
+func ConnectionHint(t aws.Target, localPort string) string {
+	switch aws.EngineName(t.Engine, t.Port) {
+	case "DocumentDB":
+		return strings.Join([]string{
+			"💡 DocumentDB requires TLS. Download the CA bundle first:",
+			fmt.Sprintf("   curl -sO %s", caBundleURL),
+			fmt.Sprintf("   mongosh --tls --tlsCAFile global-bundle.pem --tlsAllowInvalidHostnames --host 127.0.0.1:%s --retryWrites=false -u <user>", localPort),
+		}, "\n")
+	case "Neptune":
+		return strings.Join([]string{
+			"💡 Neptune requires TLS. Download the CA bundle and keep the cluster hostname for certificate checks:",
+			fmt.Sprintf("   curl -sO %s", caBundleURL),
+			fmt.Sprintf("   curl --cacert global-bundle.pem --resolve %s:%s:127.0.0.1 https://%s:%s/status", t.Endpoint, localPort, t.Endpoint, localPort),
+		}, "\n")
+	case "MySQL":
+		return fmt.Sprintf("💡 mysql -h 127.0.0.1 -P %s -u <user> -p", localPort)
+	case "PostgreSQL":
+		return fmt.Sprintf("💡 psql -h 127.0.0.1 -p %s -U <user>", localPort)
+	case "Redis", "Valkey":
+		// serverless caches and MemoryDB always enforce in-transit encryption
+		if strings.HasPrefix(t.Role, "serverless") || t.Role == "memorydb" {
+			return fmt.Sprintf("💡 redis-cli --tls --sni %s -h 127.0.0.1 -p %s", t.Endpoint, localPort)
+		}
+		return fmt.Sprintf("💡 redis-cli -h 127.0.0.1 -p %s", localPort)
+	case "Redshift":
+		return fmt.Sprintf("💡 psql -h 127.0.0.1 -p %s -U <user> -d dev", localPort)
+	case "OpenSearch", "Elasticsearch":
+		return fmt.Sprintf("💡 curl --resolve %s:%s:127.0.0.1 https://%s:%s/_cluster/health", t.Endpoint, localPort, t.Endpoint, localPort)
+	case "Kafka":
+		return strings.Join([]string{
+			"💡 Kafka clients reconnect to each broker's advertised hostname; map it to the tunnel first:",
+			fmt.Sprintf("   echo '127.0.0.1 %s' | sudo tee -a /etc/hosts", t.Endpoint),
+			fmt.Sprintf("   kafka-topics.sh --bootstrap-server %s:%s --list", t.Endpoint, localPort),
+		}, "\n")
+	case "Memcached":
+		return fmt.Sprintf("💡 echo stats | nc 127.0.0.1 %s", localPort)
+	default:
+		return ""
+	}
+}
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
@@ -9,11 +10,11 @@ import (
 )
 
 // PromptProfile prompts user to select an AWS profile
-func PromptProfile(profiles []string) (string, error) {
-	// display labels with emoji
+func PromptProfile(profiles []aws.Profile) (string, error) {
+	// display labels with emoji and the details that tell similar profiles apart
 	var labels []string
 	for _, p := range profiles {
-		labels = append(labels, fmt.Sprintf("☁️ %s", p))
+		labels = append(labels, FormatProfileLabel(p))
 	}
 
 	prompt := promptui.Select{
@@ -28,17 +29,35 @@ func PromptProfile(profiles []string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return profiles[idx], nil
+	return profiles[idx].Name, nil
+}
+
+// FormatProfileLabel returns a pretty label for profile selection
+func FormatProfileLabel(p aws.Profile) string {
+	var details []string
+	if p.AccountID != "" {
+		details = append(details, p.AccountID)
+	}
+	if p.Region != "" {
+		details = append(details, p.Region)
+	}
+	if p.SSOStartURL != "" {
+		details = append(details, p.SSOStartURL)
+	}
+	if len(details) == 0 {
+		return fmt.Sprintf("☁️ %s", p.Name)
+	}
+	return fmt.Sprintf("☁️ %s (%s)", p.Name, strings.Join(details, " | "))
 }
 
 // PromptInstance prompts user to select an EC2 instance
 func PromptInstance(instances []aws.Instance) (aws.Instance, error) {
 	var labels []string
 	for _, inst := range instances {
-		labels = append(labels, fmt.Sprintf("🖥  %s (%s)", inst.Name, inst.ID))
+		labels = append(labels, FormatInstanceLabel(inst))
 	}
 	prompt := promptui.Select{
-		Label: "Select EC2 Instance",
+		Label: "Select EC2 Instance or ECS Task",
 		Items: labels,
 		Searcher: func(input string, index int) bool {
 			return strings.Contains(strings.ToLower(labels[index]), strings.ToLower(input))
@@ -51,43 +70,69 @@ func PromptInstance(instances []aws.Instance) (aws.Instance, error) {
 	return instances[idx], nil
 }
 
-// PromptDatabase prompts user to select a database
-func PromptDatabase(dbs []aws.DB) (aws.DB, error) {
+// FormatInstanceLabel returns a pretty label for a jump host, marking ECS containers and EKS nodes
+func FormatInstanceLabel(inst aws.Instance) string {
+	if aws.IsTaskTarget(inst.ID) {
+		return fmt.Sprintf("🐳 %s (ECS task)", inst.Name)
+	}
+	if inst.EKSCluster != "" {
+		return fmt.Sprintf("🖥  %s (%s) ☸️ %s/%s", inst.Name, inst.ID, inst.EKSCluster, inst.NodeGroup)
+	}
+	return fmt.Sprintf("🖥  %s (%s)", inst.Name, inst.ID)
+}
+
+// PromptTarget prompts user to select a tunnel target, starting on the first reader endpoint with preferReader
+func PromptTarget(targets []aws.Target, preferReader bool) (aws.Target, error) {
 	var labels []string
-	for _, db := range dbs {
-		labels = append(labels, FormatDBLabel(db))
+	for _, t := range targets {
+		labels = append(labels, FormatTargetLabel(t))
 	}
 	prompt := promptui.Select{
-		Label: "Select Database",
+		Label: "Select Target",
 		Items: labels,
 		Searcher: func(input string, index int) bool {
 			return strings.Contains(strings.ToLower(labels[index]), strings.ToLower(input))
 		},
 	}
-	idx, _, err := prompt.Run()
+	cursor := 0
+	if preferReader {
+		cursor = ReaderFirst(targets)
+	}
+	idx, _, err := RunSelectAt(&prompt, cursor)
 	if err != nil {
-		return aws.DB{}, err
+		return aws.Target{}, err
 	}
-	return dbs[idx], nil
+	return targets[idx], nil
 }
 
-// FilterDBsByVPC filters databases by VPC ID
-func FilterDBsByVPC(dbs []aws.DB, vpcID string) []aws.DB {
-	var filtered []aws.DB
-	for _, db := range dbs {
-		if db.VpcID == vpcID {
-			filtered = append(filtered, db)
+// FilterTargetsByVPC filters targets by VPC ID
+func FilterTargetsByVPC(targets []aws.Target, vpcID string) []aws.Target {
+	var filtered []aws.Target
+	for _, t := range targets {
+		if t.VpcID == vpcID {
+			filtered = append(filtered, t)
 		}
 	}
 	return filtered
 }
 
-// FormatDBLabel returns a pretty label for DB selection
-func FormatDBLabel(db aws.DB) string {
-	engine := aws.DetectEngineByPort(db.Port)
+// serviceLabels maps a target kind to the icon and name its entries are grouped under
+var serviceLabels = map[string][2]string{
+	aws.KindRDS:         {"🛢️", "RDS"},
+	aws.KindRDSProxy:    {"🔀", "RDS Proxy"},
+	aws.KindElastiCache: {"⚡", "ElastiCache"},
+	aws.KindMemoryDB:    {"🧠", "MemoryDB"},
+	aws.KindRedshift:    {"🏭", "Redshift"},
+	aws.KindOpenSearch:  {"🔎", "OpenSearch"},
+	aws.KindMSK:         {"📨", "MSK"},
+}
+
+// FormatTargetLabel returns a pretty label for target selection, prefixed by its service
+func FormatTargetLabel(t aws.Target) string {
+	engine := aws.EngineName(t.Engine, t.Port)
 
 	roleLabel := ""
-	switch db.Role {
+	switch t.Role {
 	case "writer":
 		roleLabel = "✍️ Writer"
 	case "reader":
@@ -98,21 +143,124 @@ func FormatDBLabel(db aws.DB) string {
 		roleLabel = "📘 Replica"
 	case "instance":
 		roleLabel = "🧩 Instance"
+	case "custom":
+		roleLabel = "🎯 Custom"
+	case "member-writer":
+		roleLabel = fmt.Sprintf("✍️ Member writer %s", t.Class)
+	case "member-reader":
+		roleLabel = fmt.Sprintf("📖 Member reader %s", t.Class)
+	case "serverless":
+		roleLabel = fmt.Sprintf("☁️ Serverless %s", t.Identifier)
+	case "serverless-reader":
+		roleLabel = fmt.Sprintf("☁️ Serverless reader %s", t.Identifier)
+	case "memorydb":
+		roleLabel = fmt.Sprintf("🧠 Cluster %s", t.Identifier)
+	case "proxy":
+		roleLabel = fmt.Sprintf("🔀 Proxy %s", t.Identifier)
+	case "proxy-reader":
+		roleLabel = fmt.Sprintf("🔀 Proxy read-only %s", t.Identifier)
+	case "proxy-custom":
+		roleLabel = fmt.Sprintf("🔀 Proxy endpoint %s", t.Identifier)
+	case "leader":
+		roleLabel = fmt.Sprintf("🏭 Leader %s", t.Identifier)
+	case "domain":
+		roleLabel = fmt.Sprintf("🔎 Domain %s", t.Identifier)
+	case "broker-plaintext":
+		roleLabel = fmt.Sprintf("📨 Broker %s (plaintext)", t.Identifier)
+	case "broker-tls":
+		roleLabel = fmt.Sprintf("📨 Broker %s (TLS)", t.Identifier)
+	case "broker-sasl-scram":
+		roleLabel = fmt.Sprintf("📨 Broker %s (SASL/SCRAM)", t.Identifier)
+	case "broker-sasl-iam":
+		roleLabel = fmt.Sprintf("📨 Broker %s (IAM)", t.Identifier)
 	default:
-		if strings.HasPrefix(db.Role, "redis") {
-			if strings.Contains(db.Role, "primary") {
+		if strings.HasPrefix(t.Role, "redis") {
+			if strings.Contains(t.Role, "primary") {
 				roleLabel = "📕 Redis Primary"
 			} else {
 				roleLabel = "📘 Redis Replica"
 			}
-		} else if strings.HasPrefix(db.Role, "memcached") {
+		} else if t.Role == "memcached-node" {
+			roleLabel = "📗 Memcached node"
+		} else if strings.HasPrefix(t.Role, "memcached") {
 			roleLabel = "📗 Memcached"
-		} else if strings.HasPrefix(db.Role, "valkey") {
+		} else if strings.HasPrefix(t.Role, "valkey") {
 			roleLabel = "📙 Valkey"
 		} else {
 			roleLabel = "❔"
 		}
 	}
 
-	return fmt.Sprintf("🛢️ [%s] %s - %s:%s", engine, roleLabel, db.Endpoint, db.Port)
+	icon, service := "❔", t.Kind
+	if l, ok := serviceLabels[t.Kind]; ok {
+		icon, service = l[0], l[1]
+	}
+	tag := service
+	if engine != service && engine != "Unknown" {
+		tag = fmt.Sprintf("%s · %s", service, engine)
+	}
+
+	label := fmt.Sprintf("%s [%s] %s - %s:%s", icon, tag, roleLabel, t.Endpoint, t.Port)
+	// member, custom and additional proxy endpoints are listed under their cluster or proxy
+	if t.Role == "custom" || strings.HasPrefix(t.Role, "member-") || strings.HasPrefix(t.Role, "proxy-") {
+		label = fmt.Sprintf("   └ %s - %s:%s", roleLabel, t.Endpoint, t.Port)
+	}
+	if t.Source != "" {
+		label += fmt.Sprintf(" (replica of %s)", t.Source)
+	}
+	return label
+}
+
+// PrintWarnings reports partial discovery failures so missing entries are not a surprise
+func PrintWarnings(warnings []aws.Warning) {
+	if len(warnings) == 0 {
+		return
+	}
+	fmt.Println("⚠️ Some resources could not be listed:")
+	for _, w := range warnings {
+		fmt.Printf("   • %s\n", w)
+	}
+}
+
+// ConfirmTyped asks the user to type expected exactly, failing on anything else. It talks to the
+// terminal directly, since stdin and stdout may carry an ssh stream, and fails without one.
+func ConfirmTyped(label, expected string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("confirmation needs a terminal: %w", err)
+	}
+	defer tty.Close()
+
+	prompt := promptui.Prompt{
+		Label:  label,
+		Stdin:  tty,
+		Stdout: tty,
+		Validate: func(input string) error {
+			if input != expected {
+				return fmt.Errorf("type %s to confirm", expected)
+			}
+			return nil
+		},
+	}
+	_, err = prompt.Run()
+	return err
+}
+
+// RunSelectAt runs a select prompt with the cursor on item pos, scrolled so that the item is visible
+func RunSelectAt(prompt *promptui.Select, pos int) (int, string, error) {
+	if prompt.Size == 0 {
+		// promptui's default
+		prompt.Size = 5
+	}
+	return prompt.RunCursorAt(pos, max(0, pos-prompt.Size+1))
+}
+
+// ReaderFirst returns the position of the first reader endpoint in targets, or 0 if there is none
+func ReaderFirst(targets []aws.Target) int {
+	for i, t := range targets {
+		if aws.IsReaderRole(t.Role) {
+			return i
+		}
+	}
+	return 0
 }
@@ -15,6 +15,11 @@ var (
 		"memcached":         "11211",
 		"oracle":            "1521",
 		"mongodb":           "27017",
+		"docdb":             "27017",
+		"neptune":           "8182",
+		"redshift":          "5439",
+		"opensearch":        "443",
+		"kafka":             "9092",
 	}
 
 	portToEngine = map[string]string{
@@ -25,5 +30,7 @@ var (
 		"11211": "Memcached",
 		"1521":  "Oracle",
 		"27017": "MongoDB",
+		"8182":  "Neptune",
+		"5439":  "Redshift",
 	}
 )
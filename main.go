@@ -8,6 +8,8 @@ import (
 	"syscall"
 
 	"github.com/ilkerispir/aws-ssm-connect/cmd"
+	"github.com/ilkerispir/aws-ssm-connect/internal/aws"
+	"github.com/ilkerispir/aws-ssm-connect/internal/settings"
 )
 
 func main() {
@@ -21,25 +23,63 @@ func main() {
 	ssm := flag.Bool("ssm", false, "Start standard SSM shell session to EC2")
 	help := flag.Bool("help", false, "Show usage information")
 	version := flag.Bool("version", false, "Show version")
-	dbproxy := flag.Bool("db-port-forward", false, "Start port-forward to DB proxy via EC2")
+	dbproxy := flag.Bool("db-port-forward", false, "Start port-forward to a selected target via EC2")
+	refresh := flag.Bool("refresh", false, "Bypass the discovery cache and fetch fresh results")
+	forward := flag.Bool("forward", false, "Port-forward to a port on a selected EC2 instance")
+	remotePort := flag.Int("remote-port", 0, "Port on the instance to forward to (with --forward)")
+	document := flag.String("document", "", "Session Manager document for --ssm (default SSM-SessionManagerRunShell)")
+	pickDocument := flag.Bool("pick-document", false, "Choose the --ssm session document from the account's documents")
+	runAs := flag.String("run-as", "", "OS user to run the --ssm shell as")
+	shellCommand := flag.String("command", "", "Shell or initial command to run in the --ssm session")
+	remoteHost := flag.String("remote-host", "", "Tunnel to any host:port reachable from a selected EC2 instance")
+	ttl := flag.Duration("ttl", 0, "Close port-forwards after this long, e.g. 2h")
+	idleTimeout := flag.Duration("idle-timeout", 0, "Close port-forwards after this long without traffic, e.g. 30m")
 	flag.Parse()
 
-	if *ssm || *dbproxy || (*profile == "" && *filter != "") {
+	cmd.SetTunnelLimits(*ttl, *idleTimeout)
+
+	if *ssm || *dbproxy || *forward || *remoteHost != "" || flag.Arg(0) == "run" || flag.Arg(0) == "cp" || flag.Arg(0) == "shell" || flag.Arg(0) == "eks-forward" || (*profile == "" && *filter != "") {
 		if err := cmd.SelectProfileIfEmpty(profile); err != nil {
 			log.Fatalf("profile selection failed: %v", err)
 		}
 	}
 
-	// Graceful cleanup on Ctrl+C or SIGTERM
-	c := make(chan os.Signal, 1)
-	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-c
-		cmd.CleanupAndExit()
-	}()
+	// Graceful cleanup on Ctrl+C or SIGTERM; the supervisor handles its own signals
+	if flag.Arg(0) != "supervise" {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-c
+			cmd.CleanupAndExit()
+		}()
+	}
 
 	// Command dispatch
 	switch {
+	case flag.Arg(0) == "supervise":
+		cmd.Supervise(flag.Args()[1:])
+	case flag.Arg(0) == "cache":
+		cmd.CacheCommand(flag.Args()[1:])
+	case flag.Arg(0) == "proxy-command":
+		cmd.ProxyCommand(*profile, flag.Args()[1:])
+	case flag.Arg(0) == "run":
+		if err := cmd.RunCommand(*profile, flag.Args()[1:]); err != nil {
+			log.Fatalf("run failed: %v", err)
+		}
+	case flag.Arg(0) == "eks-forward":
+		if err := cmd.EKSForward(*profile, flag.Args()[1:], *port, *refresh); err != nil {
+			log.Fatalf("port forwarding failed: %v", err)
+		}
+	case flag.Arg(0) == "shell":
+		if err := cmd.Shell(*profile, flag.Args()[1:]); err != nil {
+			log.Fatalf("ECS Exec session failed: %v", err)
+		}
+	case flag.Arg(0) == "cp":
+		if err := cmd.CopyFile(*profile, flag.Args()[1:]); err != nil {
+			log.Fatalf("copy failed: %v", err)
+		}
+	case flag.Arg(0) == "ssh-config":
+		cmd.SSHConfig(*profile, flag.Args()[1:], *refresh)
 	case *help:
 		cmd.ShowHelper()
 	case *version:
@@ -51,19 +91,31 @@ func main() {
 	case *killAll:
 		cmd.KillAllSessions()
 	case *dbproxy:
-		if err := cmd.ConnectToDBProxy(*profile, *port); err != nil {
+		if err := cmd.ConnectToDBProxy(*profile, *port, *refresh); err != nil {
 			log.Fatalf("DB proxy connection failed: %v", err)
 		}
+	case *remoteHost != "":
+		if err := cmd.ForwardRemoteHost(*profile, *filter, *remoteHost, *port, *refresh); err != nil {
+			log.Fatalf("port forwarding failed: %v", err)
+		}
+	case *forward:
+		if err := cmd.ForwardInstancePort(*profile, *filter, *remotePort, *port, *refresh); err != nil {
+			log.Fatalf("port forwarding failed: %v", err)
+		}
 	case *profile != "" && *filter != "":
-		cmd.QuickConnect(*profile, *filter, *port)
+		cmd.QuickConnect(*profile, *filter, *port, *refresh)
 	case *ssm:
-		err := cmd.StartSSMSession(*profile)
+		override := settings.Session{Document: *document, RunAs: *runAs, Command: *shellCommand}
+		err := cmd.StartSSMSession(*profile, *refresh, override, *pickDocument)
 		if err != nil {
 			log.Fatalf("SSM session failed: %v", err)
 		}
 	default:
-		if err := cmd.Interactive(); err != nil {
+		if err := cmd.Interactive(*refresh); err != nil {
 			panic(err)
 		}
 	}
+
+	// Let background cache refreshes finish writing before exiting
+	aws.WaitForRefresh()
 }